@@ -0,0 +1,202 @@
+package goapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultRefreshLeeway is how long before expiry a token gets renewed
+const defaultRefreshLeeway = time.Minute
+
+// Credentials are the email and password used to log in
+type Credentials struct {
+	Email    string
+	Password string
+}
+
+// CredentialsProvider supplies login credentials whenever the client needs to
+// (re-)authenticate, so that they need not be kept in application code
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc adapts a function to a CredentialsProvider
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a CredentialsProvider that always returns the
+// given email and password
+func StaticCredentials(email, password string) CredentialsProvider {
+	return CredentialsFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{Email: email, Password: password}, nil
+	})
+}
+
+// EnvCredentials returns a CredentialsProvider that reads the email and
+// password from the named environment variables on every login
+func EnvCredentials(emailVar, passwordVar string) CredentialsProvider {
+	return CredentialsFunc(func(ctx context.Context) (Credentials, error) {
+		email, password := os.Getenv(emailVar), os.Getenv(passwordVar)
+		if email == "" || password == "" {
+			return Credentials{}, fmt.Errorf("credentials not set in %s and %s", emailVar, passwordVar)
+		}
+		return Credentials{Email: email, Password: password}, nil
+	})
+}
+
+// WithCredentials makes the client log in on its own: before the first
+// request, shortly before the token expires, and once after a 401
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithRefreshPath makes the client renew expiring tokens by POSTing the
+// current token to path instead of logging in again. The endpoint must
+// answer like /login. Logging in with the configured credentials remains
+// the fallback when the refresh fails.
+func WithRefreshPath(path string) Option {
+	return func(c *Client) {
+		c.refreshPath = path
+	}
+}
+
+// WithRefreshLeeway sets how long before its expiry a token is renewed
+func WithRefreshLeeway(leeway time.Duration) Option {
+	return func(c *Client) {
+		c.refreshLeeway = leeway
+	}
+}
+
+// TokenExpiry returns the expiry time from the exp claim of the current
+// token, and false if there is no token or it carries no exp claim
+func (c *Client) TokenExpiry() (time.Time, bool) {
+	expiry := c.tokens.get(c.tenantName).expiry
+	return expiry, !expiry.IsZero()
+}
+
+// canAuthenticate reports whether the client can obtain tokens on its own
+func (c *Client) canAuthenticate() bool {
+	return c.credentials != nil
+}
+
+// currentToken returns the token to send, renewing it first when it is
+// missing or about to expire and the client knows how to renew it
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	current := c.tokens.get(c.tenantName)
+	if !c.needsRenewal(current) || (!c.canAuthenticate() && (c.refreshPath == "" || current.token == "")) {
+		return current.token, nil
+	}
+
+	c.tokens.authMu.Lock()
+	defer c.tokens.authMu.Unlock()
+
+	// Another goroutine may have renewed the token while we waited
+	current = c.tokens.get(c.tenantName)
+	if !c.needsRenewal(current) {
+		return current.token, nil
+	}
+
+	if err := c.renewToken(ctx, current.token); err != nil {
+		// A token that has not actually expired yet is still worth sending
+		if current.token != "" && time.Now().Before(current.expiry) {
+			return current.token, nil
+		}
+		return "", fmt.Errorf("error renewing JWT token: %w", err)
+	}
+	return c.tokens.get(c.tenantName).token, nil
+}
+
+func (c *Client) needsRenewal(current tenantToken) bool {
+	if current.token == "" {
+		return true
+	}
+	if current.expiry.IsZero() {
+		return false
+	}
+	leeway := c.refreshLeeway
+	if leeway == 0 {
+		leeway = defaultRefreshLeeway
+	}
+	return time.Now().Add(leeway).After(current.expiry)
+}
+
+// renewToken obtains fresh tokens through the refresh endpoint, falling back
+// to logging in with the configured credentials. Callers hold authMu.
+func (c *Client) renewToken(ctx context.Context, token string) (err error) {
+	defer func() {
+		c.recordTokenRefresh(ctx, err)
+	}()
+
+	var refreshErr error
+	if c.refreshPath != "" && token != "" {
+		refreshCtx := withOperation(ctx, "RefreshToken")
+		refreshErr = c.storeTokens(c.requestTokens(refreshCtx, c.refreshPath, struct{}{}, token))
+		if refreshErr == nil {
+			return nil
+		}
+	}
+	if !c.canAuthenticate() {
+		return refreshErr
+	}
+	return c.loginWithCredentials(ctx)
+}
+
+// reauthenticate logs in again after the server rejected stale with a 401.
+// It does nothing if another request already replaced stale.
+func (c *Client) reauthenticate(ctx context.Context, stale string) error {
+	c.tokens.authMu.Lock()
+	defer c.tokens.authMu.Unlock()
+
+	if c.tokens.get(c.tenantName).token != stale {
+		return nil
+	}
+	// The server no longer accepts the token, so skip the refresh endpoint
+	err := c.loginWithCredentials(ctx)
+	c.recordTokenRefresh(ctx, err)
+	return err
+}
+
+func (c *Client) loginWithCredentials(ctx context.Context) error {
+	credentials, err := c.credentials.Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting credentials: %w", err)
+	}
+	return c.LoginContext(ctx, credentials.Email, credentials.Password)
+}
+
+// jwtExpiry reads the exp claim of a JWT without verifying its signature
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error decoding JWT payload: %w", err)
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("error decoding JWT claims: %w", err)
+	}
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("JWT has no exp claim")
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid JWT exp claim: %w", err)
+	}
+	return time.Unix(int64(exp), 0), nil
+}
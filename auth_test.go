@@ -0,0 +1,59 @@
+package goapi_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi/goapitest"
+)
+
+// trace returns the method, path and status of every request after the
+// first skip
+func trace(srv *goapitest.Server, skip int) []string {
+	var lines []string
+	for _, req := range srv.Requests()[skip:] {
+		lines = append(lines, req.Method+" "+req.Path+" "+http.StatusText(req.Status))
+	}
+	return lines
+}
+
+func TestReauthenticatesOn401(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	skip := len(srv.Requests())
+	srv.ExpireTokens()
+
+	if _, err := client.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /products Unauthorized",
+		"POST /login OK",
+		"GET /products OK",
+	}
+	if got := trace(srv, skip); !slices.Equal(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestRenewsTokenBeforeExpiry(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	// Tokens expire within the default leeway, so every call renews first
+	srv.TokenTTL = 30 * time.Second
+	if err := client.Login(goapitest.TestEmail, goapitest.TestPassword); err != nil {
+		t.Fatal(err)
+	}
+	skip := len(srv.Requests())
+
+	if _, err := client.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"POST /login OK",
+		"GET /products OK",
+	}
+	if got := trace(srv, skip); !slices.Equal(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
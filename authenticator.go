@@ -0,0 +1,274 @@
+package goapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator adds credentials to every outgoing request. Without
+// WithAuthenticator a client sends the tenant's JWT token obtained by Login
+// or WithCredentials.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// Reauthenticator is implemented by Authenticators that can recover from a
+// 401 response. Reauthenticate is called once with the rejected request and
+// reports whether the request should be replayed.
+type Reauthenticator interface {
+	Reauthenticate(ctx context.Context, rejected *http.Request) (bool, error)
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator
+type AuthenticatorFunc func(ctx context.Context, req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// TokenSource supplies bearer tokens
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// InvalidatingTokenSource is a TokenSource that can drop a token the server
+// rejected so that the next call to Token fetches a new one
+type InvalidatingTokenSource interface {
+	TokenSource
+	Invalidate(token string)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithAuthenticator replaces the built-in JWT login with authenticator
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(c *Client) {
+		c.authenticator = authenticator
+	}
+}
+
+// WithTokenSource authenticates every request with a bearer token from source
+func WithTokenSource(source TokenSource) Option {
+	return WithAuthenticator(BearerToken(source))
+}
+
+// StaticToken returns a TokenSource that always returns token, e.g. a user
+// JWT that a gateway already holds
+func StaticToken(token string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// BearerToken returns an Authenticator that sends tokens from source in the
+// Authorization header. If source is an InvalidatingTokenSource, a 401 drops
+// the rejected token and the request is replayed once with a new one.
+func BearerToken(source TokenSource) Authenticator {
+	return &bearerAuthenticator{source: source}
+}
+
+type bearerAuthenticator struct {
+	source TokenSource
+}
+
+func (a *bearerAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.source.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting token: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	return nil
+}
+
+func (a *bearerAuthenticator) Reauthenticate(ctx context.Context, rejected *http.Request) (bool, error) {
+	source, ok := a.source.(InvalidatingTokenSource)
+	if !ok {
+		return false, nil
+	}
+	source.Invalidate(strings.TrimPrefix(rejected.Header.Get("Authorization"), "Bearer "))
+	return true, nil
+}
+
+// APIKey returns an Authenticator that sends key in the given header, or in
+// X-API-Key when header is empty
+func APIKey(header, key string) Authenticator {
+	if header == "" {
+		header = "X-API-Key"
+	}
+	return AuthenticatorFunc(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set(header, key)
+		return nil
+	})
+}
+
+// ClientCredentialsConfig describes an OAuth2 client-credentials grant
+type ClientCredentialsConfig struct {
+	TokenURL       string
+	ClientID       string
+	ClientSecret   string
+	Scopes         []string
+	EndpointParams url.Values   // Extra form values sent to TokenURL
+	HTTPClient     *http.Client // Defaults to http.DefaultClient
+}
+
+// ClientCredentials returns a TokenSource that fetches access tokens with the
+// OAuth2 client-credentials grant and caches them until shortly before they
+// expire
+func ClientCredentials(config ClientCredentialsConfig) InvalidatingTokenSource {
+	return &clientCredentialsSource{config: config}
+}
+
+type clientCredentialsSource struct {
+	config ClientCredentialsConfig
+
+	mu     sync.Mutex // Mutex for thread-safe access to token and expiry
+	token  string
+	expiry time.Time
+}
+
+type clientCredentialsResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (s *clientCredentialsSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Add(defaultRefreshLeeway).Before(s.expiry)) {
+		return s.token, nil
+	}
+
+	form := url.Values{}
+	for key, values := range s.config.EndpointParams {
+		form[key] = values
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+
+	httpClient := s.config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp)
+	}
+
+	var response clientCredentialsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("error decoding token response: %w", err)
+	}
+	if response.AccessToken == "" {
+		return "", fmt.Errorf("token response has no access_token")
+	}
+
+	s.token = response.AccessToken
+	s.expiry = time.Time{}
+	if response.ExpiresIn > 0 {
+		s.expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return s.token, nil
+}
+
+func (s *clientCredentialsSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// sessionAuthenticator is the built-in Authenticator: it sends the JWT token
+// of the client's tenant and renews it with the configured credentials
+type sessionAuthenticator struct {
+	c *Client
+}
+
+func (a sessionAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.c.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	return nil
+}
+
+func (a sessionAuthenticator) Reauthenticate(ctx context.Context, rejected *http.Request) (bool, error) {
+	if !a.c.canAuthenticate() {
+		return false, nil
+	}
+	stale := strings.TrimPrefix(rejected.Header.Get("Authorization"), "Bearer ")
+	if err := a.c.reauthenticate(ctx, stale); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// auth returns the Authenticator in effect for the client
+func (c *Client) auth() Authenticator {
+	if c.authenticator != nil {
+		return c.authenticator
+	}
+	return sessionAuthenticator{c: c}
+}
+
+// credentialKey identifies the credentials a GET of url is sent with under
+// ctx. It is a digest of the headers and query the Authenticator sets, so
+// that two callers share responses only if they authenticate the same way,
+// e.g. not two users of a gateway whose Authenticator reads JWTs from ctx.
+func (c *Client) credentialKey(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating new request: %w", err)
+	}
+	if err := c.auth().Authenticate(ctx, req); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s: %q\n", name, req.Header[name])
+	}
+	fmt.Fprintf(hash, "?%s", req.URL.RawQuery)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,223 @@
+package goapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/classify-api/goapi"
+)
+
+// tokenServer is an OAuth2 token endpoint issuing numbered access tokens
+type tokenServer struct {
+	*httptest.Server
+	expiresIn int
+
+	mu    sync.Mutex
+	forms []map[string]string
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	s := &tokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		id, secret, _ := r.BasicAuth()
+		s.mu.Lock()
+		s.forms = append(s.forms, map[string]string{
+			"grant_type": r.PostForm.Get("grant_type"),
+			"scope":      r.PostForm.Get("scope"),
+			"audience":   r.PostForm.Get("audience"),
+			"client":     id + ":" + secret,
+		})
+		n := len(s.forms)
+		s.mu.Unlock()
+		if secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, n, s.expiresIn)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) requests() []map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]string(nil), s.forms...)
+}
+
+func (s *tokenServer) source(secret string) goapi.InvalidatingTokenSource {
+	return goapi.ClientCredentials(goapi.ClientCredentialsConfig{
+		TokenURL:       s.URL,
+		ClientID:       "kiosk",
+		ClientSecret:   secret,
+		Scopes:         []string{"read", "write"},
+		EndpointParams: map[string][]string{"audience": {"classify"}},
+	})
+}
+
+func TestClientCredentialsCachesToken(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	source := tokens.source("s3cret")
+	ctx := context.Background()
+
+	for range 3 {
+		token, err := source.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "token-1" {
+			t.Errorf("token = %q, want token-1", token)
+		}
+	}
+	requests := tokens.requests()
+	if len(requests) != 1 {
+		t.Fatalf("token requests = %d, want 1", len(requests))
+	}
+	want := map[string]string{"grant_type": "client_credentials", "scope": "read write", "audience": "classify", "client": "kiosk:s3cret"}
+	for key, value := range want {
+		if requests[0][key] != value {
+			t.Errorf("%s = %q, want %q", key, requests[0][key], value)
+		}
+	}
+
+	// Invalidating another token keeps the cached one
+	source.Invalidate("token-0")
+	if token, _ := source.Token(ctx); token != "token-1" {
+		t.Errorf("token after invalidating a stale one = %q, want token-1", token)
+	}
+	source.Invalidate("token-1")
+	if token, _ := source.Token(ctx); token != "token-2" {
+		t.Errorf("token after invalidation = %q, want token-2", token)
+	}
+}
+
+func TestClientCredentialsRenewsWithinLeeway(t *testing.T) {
+	// Tokens living 30s are already inside the one minute leeway
+	tokens := newTokenServer(t, 30)
+	source := tokens.source("s3cret")
+
+	for i := 1; i <= 2; i++ {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("token-%d", i); token != want {
+			t.Errorf("token = %q, want %q", token, want)
+		}
+	}
+}
+
+func TestClientCredentialsError(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	_, err := tokens.source("wrong").Token(context.Background())
+	var apiErr *goapi.APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, goapi.ErrUnauthorized) || apiErr.Message != "invalid_client" {
+		t.Errorf("err = %v, want a 401 APIError", err)
+	}
+}
+
+// apiServer answers 401 to tokens in rejected and records the credentials
+// of every request
+type apiServer struct {
+	*httptest.Server
+	header string
+
+	mu       sync.Mutex
+	rejected map[string]bool
+	seen     []string
+}
+
+func newAPIServer(t *testing.T, header string, rejected ...string) *apiServer {
+	s := &apiServer{header: header, rejected: make(map[string]bool)}
+	s.reject(rejected...)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials := r.Header.Get(s.header)
+		s.mu.Lock()
+		s.seen = append(s.seen, credentials)
+		rejected := s.rejected[credentials]
+		s.mu.Unlock()
+		if rejected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *apiServer) reject(credentials ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range credentials {
+		s.rejected[c] = true
+	}
+}
+
+func (s *apiServer) credentials() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.seen...)
+}
+
+func TestClientCredentialsReplaysOn401(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api := newAPIServer(t, "Authorization", "Bearer token-1")
+	client := goapi.New(api.URL, goapi.WithTokenSource(tokens.source("s3cret")))
+
+	if _, err := client.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+	got := api.credentials()
+	if len(got) != 2 || got[0] != "Bearer token-1" || got[1] != "Bearer token-2" {
+		t.Errorf("credentials sent = %q, want token-1 then token-2", got)
+	}
+
+	// A token that keeps being rejected is replayed only once
+	api.reject("Bearer token-2", "Bearer token-3")
+	if _, err := client.GetProducts(); !errors.Is(err, goapi.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+	if got := api.credentials(); len(got) != 4 {
+		t.Errorf("credentials sent = %q, want one replay", got)
+	}
+}
+
+func TestBearerTokenWithoutInvalidation(t *testing.T) {
+	api := newAPIServer(t, "Authorization", "Bearer gateway-jwt")
+	client := goapi.New(api.URL, goapi.WithTokenSource(goapi.StaticToken("gateway-jwt")))
+
+	if _, err := client.GetProducts(); !errors.Is(err, goapi.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+	if got := api.credentials(); len(got) != 1 {
+		t.Errorf("credentials sent = %q, want no replay", got)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	tests := []struct {
+		header     string
+		wantHeader string
+	}{
+		{header: "", wantHeader: "X-API-Key"},
+		{header: "X-Classify-Key", wantHeader: "X-Classify-Key"},
+	}
+	for _, tt := range tests {
+		api := newAPIServer(t, tt.wantHeader)
+		client := goapi.New(api.URL, goapi.WithAuthenticator(goapi.APIKey(tt.header, "key-1")))
+		if _, err := client.GetProducts(); err != nil {
+			t.Fatal(err)
+		}
+		if got := api.credentials(); len(got) != 1 || got[0] != "key-1" {
+			t.Errorf("%s = %q, want key-1", tt.wantHeader, got)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package goapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker of a host or endpoint is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests flow normally
+	CircuitOpen                         // Requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen                     // A few probe requests test the backend
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerConfig configures the circuit breaker of a client. Zero
+// values select the defaults noted on each field.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit (default 5)
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before letting probe
+	// requests through (default 30s)
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of concurrent probe requests allowed
+	// while half-open (default 1)
+	HalfOpenRequests int
+
+	// SuccessThreshold is the number of successful probes that closes the
+	// circuit again (default 1)
+	SuccessThreshold int
+
+	// PerEndpoint keeps a separate circuit per operation, such as
+	// "GetProducts", instead of one per host
+	PerEndpoint bool
+
+	// IsFailure decides which outcomes count as failures. By default these
+	// are transport errors, including missed deadlines, and 5xx responses.
+	// Requests cancelled by the caller count neither way.
+	IsFailure func(resp *http.Response, err error) bool
+
+	// OnStateChange, if set, is called whenever a circuit changes state
+	OnStateChange func(name string, from, to CircuitState)
+}
+
+// WithCircuitBreaker puts a circuit breaker in front of every request
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(c *Client) {
+		if config.FailureThreshold <= 0 {
+			config.FailureThreshold = 5
+		}
+		if config.OpenTimeout <= 0 {
+			config.OpenTimeout = 30 * time.Second
+		}
+		if config.HalfOpenRequests <= 0 {
+			config.HalfOpenRequests = 1
+		}
+		if config.SuccessThreshold <= 0 {
+			config.SuccessThreshold = 1
+		}
+		if config.IsFailure == nil {
+			config.IsFailure = defaultIsFailure
+		}
+		c.breakers = &circuitBreakers{config: config, circuits: make(map[string]*circuit)}
+	}
+}
+
+func defaultIsFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// CircuitStates returns the state of every circuit the client has used,
+// keyed by host or by host and operation
+func (c *Client) CircuitStates() map[string]CircuitState {
+	states := make(map[string]CircuitState)
+	if c.breakers == nil {
+		return states
+	}
+	c.breakers.mu.Lock()
+	circuits := make(map[string]*circuit, len(c.breakers.circuits))
+	for name, circuit := range c.breakers.circuits {
+		circuits[name] = circuit
+	}
+	c.breakers.mu.Unlock()
+
+	for name, circuit := range circuits {
+		states[name] = circuit.currentState(c.breakers.config)
+	}
+	return states
+}
+
+// circuitBreakers holds the circuits of a client and its tenant views
+type circuitBreakers struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex // Mutex for thread-safe access to circuits
+	circuits map[string]*circuit
+}
+
+func (b *circuitBreakers) get(host, operation string) *circuit {
+	name := host
+	if b.config.PerEndpoint {
+		name = host + " " + operation
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[name]
+	if !ok {
+		c = &circuit{name: name}
+		b.circuits[name] = c
+	}
+	return c
+}
+
+type circuit struct {
+	name string
+
+	mu        sync.Mutex // Mutex for thread-safe access to the fields below
+	state     CircuitState
+	failures  int
+	successes int
+	inFlight  int
+	openedAt  time.Time
+
+	// generation changes with every state change, so that outcomes of
+	// requests admitted in an earlier state can be told apart
+	generation uint64
+}
+
+// allow reports whether a request may be sent; every allowed request must be
+// followed by a call to record or release with the returned generation
+func (c *circuit) allow(config CircuitBreakerConfig) (uint64, error) {
+	c.mu.Lock()
+	from := c.state
+	c.promote(config)
+	allowed := true
+	switch c.state {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		if c.inFlight >= config.HalfOpenRequests {
+			allowed = false
+		} else {
+			c.inFlight++
+		}
+	}
+	to := c.state
+	generation := c.generation
+	c.mu.Unlock()
+
+	c.notify(config, from, to)
+	if !allowed {
+		return 0, fmt.Errorf("%s: %w", c.name, ErrCircuitOpen)
+	}
+	return generation, nil
+}
+
+// record feeds the outcome of an allowed request into the circuit. Outcomes
+// of requests admitted before the last state change are ignored: a request
+// sent while closed says nothing about a half-open probe, and must not
+// release a probe slot.
+func (c *circuit) record(config CircuitBreakerConfig, generation uint64, failed bool) {
+	c.mu.Lock()
+	if generation != c.generation {
+		c.mu.Unlock()
+		return
+	}
+	from := c.state
+	switch c.state {
+	case CircuitClosed:
+		if !failed {
+			c.failures = 0
+		} else if c.failures++; c.failures >= config.FailureThreshold {
+			c.trip()
+		}
+	case CircuitHalfOpen:
+		c.inFlight--
+		if failed {
+			c.trip()
+		} else if c.successes++; c.successes >= config.SuccessThreshold {
+			c.state = CircuitClosed
+			c.failures = 0
+			c.generation++
+		}
+	}
+	to := c.state
+	c.mu.Unlock()
+
+	c.notify(config, from, to)
+}
+
+// release ends an allowed request without recording an outcome, freeing its
+// probe slot. It is used for requests cancelled by the caller, which say
+// nothing about the backend.
+func (c *circuit) release(generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation == c.generation && c.state == CircuitHalfOpen {
+		c.inFlight--
+	}
+}
+
+func (c *circuit) currentState(config CircuitBreakerConfig) CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// promote moves an open circuit whose timeout has passed to half-open
+func (c *circuit) promote(config CircuitBreakerConfig) {
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= config.OpenTimeout {
+		c.state = CircuitHalfOpen
+		c.successes = 0
+		c.inFlight = 0
+		c.generation++
+	}
+}
+
+func (c *circuit) trip() {
+	c.state = CircuitOpen
+	c.openedAt = time.Now()
+	c.failures = 0
+	c.successes = 0
+	c.generation++
+}
+
+func (c *circuit) notify(config CircuitBreakerConfig, from, to CircuitState) {
+	if from != to && config.OnStateChange != nil {
+		config.OnStateChange(c.name, from, to)
+	}
+}
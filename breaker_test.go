@@ -0,0 +1,133 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitIgnoresOutcomesFromEarlierStates(t *testing.T) {
+	config := CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+		HalfOpenRequests: 1,
+		SuccessThreshold: 1,
+	}
+	c := &circuit{name: "api"}
+
+	// A slow request is admitted while closed, then another one trips the circuit
+	slow, err := c.allow(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing, err := c.allow(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.record(config, failing, true)
+	if state := c.currentState(config); state != CircuitOpen {
+		t.Fatalf("state = %v, want open", state)
+	}
+
+	time.Sleep(config.OpenTimeout)
+	probe, err := c.allow(config)
+	if err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+
+	// The slow request succeeding must neither close the circuit nor free
+	// the probe slot
+	c.record(config, slow, false)
+	if state := c.currentState(config); state != CircuitHalfOpen {
+		t.Fatalf("state after stale success = %v, want half-open", state)
+	}
+	if _, err := c.allow(config); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe admitted: %v", err)
+	}
+
+	c.record(config, probe, false)
+	if state := c.currentState(config); state != CircuitClosed {
+		t.Fatalf("state after probe success = %v, want closed", state)
+	}
+}
+
+func TestCircuitStaleFailureDoesNotReopen(t *testing.T) {
+	config := CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+		HalfOpenRequests: 1,
+		SuccessThreshold: 1,
+	}
+	c := &circuit{name: "api"}
+
+	slow, _ := c.allow(config)
+	failing, _ := c.allow(config)
+	c.record(config, failing, true)
+	time.Sleep(config.OpenTimeout)
+	probe, err := c.allow(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.record(config, probe, false)
+
+	// A failure of a request admitted before the circuit opened is old news
+	c.record(config, slow, true)
+	if state := c.currentState(config); state != CircuitClosed {
+		t.Fatalf("state = %v, want closed", state)
+	}
+}
+
+func TestCircuitTripsOnHangingBackend(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	client := New(srv.URL, WithCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      10 * time.Millisecond,
+	}))
+	state := func() CircuitState {
+		return client.CircuitStates()[srv.Listener.Addr().String()]
+	}
+
+	// Callers giving up on a hanging backend trip the circuit
+	for range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := client.GetProductsContext(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want a deadline error", err)
+		}
+	}
+	if got := state(); got != CircuitOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	// A cancelled probe proves nothing and frees the slot for the next one
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := client.GetProductsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want a cancellation", err)
+	}
+	if got := state(); got != CircuitHalfOpen {
+		t.Fatalf("state after cancelled probe = %v, want half-open", got)
+	}
+
+	healthy.Store(true)
+	if _, err := client.GetProducts(); err != nil {
+		t.Fatalf("probe after cancelled probe: %v", err)
+	}
+	if got := state(); got != CircuitClosed {
+		t.Fatalf("state after probe success = %v, want closed", got)
+	}
+}
@@ -0,0 +1,246 @@
+package goapi
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached GET response
+type CacheEntry struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified string
+	StoredAt     time.Time // When the entry was stored or last revalidated
+}
+
+// CacheStore stores cached responses. Implementations must be safe for
+// concurrent use.
+type CacheStore interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+	DeletePrefix(prefix string)
+}
+
+// WithCache caches GET responses in store, keyed by tenant, URL and
+// credentials, so a store may be shared by clients of different users.
+// Entries younger than ttl are served without contacting the server; older
+// ones are revalidated with If-None-Match or If-Modified-Since and served
+// from the cache on a 304. A successful POST, PUT, PATCH or DELETE made through the
+// same client drops the cached entries of the resource it changed.
+func WithCache(store CacheStore, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = &responseCache{store: store, ttl: ttl}
+	}
+}
+
+type responseCache struct {
+	store CacheStore
+	ttl   time.Duration
+}
+
+func (c *Client) cacheEnabled(ctx context.Context, method string) bool {
+	return c.cache != nil && method == http.MethodGet && !isStreaming(ctx)
+}
+
+// cacheKey identifies the cached response of url: entries of the same URL
+// for other credentials share the tenant and URL prefix
+func (c *Client) cacheKey(ctx context.Context, url string) (string, error) {
+	credentials, err := c.credentialKey(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return c.cacheKeyPrefix(url) + " " + credentials, nil
+}
+
+func (c *Client) cacheKeyPrefix(url string) string {
+	return c.tenantName + " " + url
+}
+
+// sendCached makes a GET request through the response cache
+func (c *Client) sendCached(ctx context.Context, url string, header http.Header) (*http.Response, int, error) {
+	key, err := c.cacheKey(ctx, url)
+	if err != nil {
+		return nil, 0, err
+	}
+	entry, cached := c.cache.store.Get(key)
+	if cached && c.cache.ttl > 0 && time.Since(entry.StoredAt) < c.cache.ttl {
+		return entry.response(), 0, nil
+	}
+	if cached {
+		if entry.ETag != "" {
+			header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, retries, err := c.send(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return nil, retries, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		c.cache.store.Set(key, entry)
+		return entry.response(), retries, nil
+	}
+
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, retries, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, retries, ctxErr
+		}
+		return nil, retries, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry = CacheEntry{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+	}
+	if entry.ETag != "" || entry.LastModified != "" || c.cache.ttl > 0 {
+		c.cache.store.Set(key, entry)
+	}
+	return resp, retries, nil
+}
+
+// response rebuilds an *http.Response from the entry
+func (e CacheEntry) response() *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode: e.StatusCode,
+		Header:     e.Header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(e.Body)),
+	}
+}
+
+// relatedResources lists the collections whose cached responses embed the
+// resource a mutation changes
+var relatedResources = map[string][]string{
+	"clock_in":                           {"time_sheets", "users"},
+	"clock_out":                          {"time_sheets", "users"},
+	"time_sheets":                        {"users"},
+	"reimbursements":                     {"users"},
+	"product_schedule_sessions":          {"product_schedules"},
+	"product_schedule_session_users":     {"product_schedule_sessions", "product_schedules"},
+	"product_schedule_session_resources": {"product_schedule_sessions", "product_schedules"},
+}
+
+// invalidateCache drops the cached responses of the resource under path and
+// of the collections embedding it, for the client's tenant and any credentials
+func (c *Client) invalidateCache(path string) {
+	path, _, _ = strings.Cut(path, "?")
+	root, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if root == "" {
+		return
+	}
+	for _, resource := range append([]string{root}, relatedResources[root]...) {
+		prefix := c.cacheKeyPrefix(c.baseURL + "/" + resource)
+		c.cache.store.DeletePrefix(prefix + " ")
+		c.cache.store.DeletePrefix(prefix + "/")
+		c.cache.store.DeletePrefix(prefix + "?")
+	}
+}
+
+// LRUCache is an in-memory CacheStore that evicts the least recently used
+// entry once it holds capacity entries
+type LRUCache struct {
+	capacity int
+
+	mu      sync.Mutex // Mutex for thread-safe access to entries and order
+	entries map[string]*list.Element
+	order   *list.List // Front is the most recently used
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache returns an empty LRUCache holding up to capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *LRUCache) Get(key string) (CacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+func (l *LRUCache) Set(key string, entry CacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruItem{key: key, entry: entry})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (l *LRUCache) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.order.Remove(element)
+		delete(l.entries, key)
+	}
+}
+
+func (l *LRUCache) DeletePrefix(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, element := range l.entries {
+		if strings.HasPrefix(key, prefix) {
+			l.order.Remove(element)
+			delete(l.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached entries
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,94 @@
+package goapi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north", goapi.WithCache(goapi.NewLRUCache(10), 0))
+	created, err := client.CreateProduct(goapi.Product{Name: "Yoga"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/products/" + created.ID
+
+	for range 2 {
+		product, err := client.GetProduct(created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if product.Name != "Yoga" {
+			t.Errorf("name = %q, want Yoga", product.Name)
+		}
+	}
+	gets := srv.RequestsTo(http.MethodGet, path)
+	if got := statuses(gets); !slices.Equal(got, []int{200, 304}) {
+		t.Fatalf("statuses = %v, want [200 304]", got)
+	}
+	if gets[1].Header.Get("If-None-Match") == "" {
+		t.Error("revalidation sent no If-None-Match")
+	}
+
+	if _, err := client.UpdateProduct(created.ID, goapi.Product{Name: "Pilates"}); err != nil {
+		t.Fatal(err)
+	}
+	product, err := client.GetProduct(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Name != "Pilates" {
+		t.Errorf("name after update = %q, want Pilates", product.Name)
+	}
+	gets = srv.RequestsTo(http.MethodGet, path)
+	if last := gets[len(gets)-1]; last.Status != http.StatusOK || last.Header.Get("If-None-Match") != "" {
+		t.Errorf("GET after update: status %d, If-None-Match %q; want a fresh 200", last.Status, last.Header.Get("If-None-Match"))
+	}
+}
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north", goapi.WithCache(goapi.NewLRUCache(10), time.Hour))
+	created, err := client.CreateProduct(goapi.Product{Name: "Yoga"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if _, err := client.GetProduct(created.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(srv.RequestsTo(http.MethodGet, "/products/"+created.ID)); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestSharedCacheKeepsUsersApart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"id":%q}]`, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer srv.Close()
+	store := goapi.NewLRUCache(10)
+
+	for _, user := range []string{"alice", "bob", "alice"} {
+		client := goapi.New(srv.URL, goapi.WithCache(store, time.Hour), goapi.WithTokenSource(goapi.StaticToken(user)))
+		users, err := client.GetUsers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if users[0].ID != user {
+			t.Errorf("%s got the cached users of %s", user, users[0].ID)
+		}
+	}
+	if got := store.Len(); got != 2 {
+		t.Errorf("cached entries = %d, want 2", got)
+	}
+}
@@ -2,21 +2,96 @@ package goapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"sync"
+	"time"
 )
 
-var (
-	BASE_URL   string
-	jwtToken   string // Updated to store single JWT token
-	TenantName string // New global variable for the tenant name
-	client     = &http.Client{}
-	mu         sync.Mutex // Mutex for thread-safe access to jwtToken
-)
+// Client is a handle on a single Classify API environment and tenant. Each
+// Client owns its own HTTP client and JWT tokens, so a process can talk to
+// several environments or tenants at once. Use ForTenant to address another
+// tenant with the tokens of the same Login.
+type Client struct {
+	baseURL    string
+	tenantName string
+	httpClient *http.Client
+	middleware []Middleware
+	handler    Handler
+
+	tracer    Tracer
+	metrics   Metrics
+	logger    *slog.Logger
+	logBodies bool
+	logPII    bool
+
+	maxResponseSize     int64
+	cache               *responseCache
+	coalescer           *coalescer
+	rateLimits          *rateLimits
+	breakers            *circuitBreakers
+	retryPolicy         RetryPolicy
+	autoIdempotencyKeys bool
+	validate            bool
+
+	authenticator Authenticator
+	credentials   CredentialsProvider
+	refreshPath   string
+	refreshLeeway time.Duration
+
+	tokens       *tokenStore
+	initialToken string
+}
+
+// Option configures a Client created by New
+type Option func(*Client)
+
+// WithHTTPClient sets the *http.Client used to make requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTenantName sets the tenant whose token is selected on Login
+func WithTenantName(tenant string) Option {
+	return func(c *Client) {
+		c.tenantName = tenant
+	}
+}
+
+// WithToken sets a JWT token for the client's tenant up front, skipping the
+// need to call Login
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.initialToken = token
+	}
+}
+
+// New creates a Client for the API at baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:             baseURL,
+		rateLimits:          &rateLimits{},
+		httpClient:          &http.Client{},
+		retryPolicy:         DefaultRetryPolicy(),
+		autoIdempotencyKeys: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.handler = c.buildHandler()
+	c.tokens = newTokenStore()
+	if c.initialToken != "" {
+		c.tokens.set(c.tenantName, c.initialToken)
+	}
+	return c
+}
 
 type LoginRequest struct {
 	User User `json:"user"`
@@ -26,17 +101,36 @@ type loginResponse struct {
 	Tokens map[string]string `json:"tokens"` // Updated to handle multiple tokens
 }
 
-func SetBaseURL(url string) {
-	BASE_URL = url
+// BaseURL returns the API base URL the client sends requests to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// SetBaseURL changes the API base URL the client sends requests to
+func (c *Client) SetBaseURL(url string) {
+	c.baseURL = url
+}
+
+// TenantName returns the tenant whose token the client uses
+func (c *Client) TenantName() string {
+	return c.tenantName
 }
 
-func SetTenantName(tenant string) {
-	TenantName = tenant
+// SetTenantName changes the tenant whose token the client uses. Tokens of
+// all tenants are kept from Login, so switching needs no new Login.
+func (c *Client) SetTenantName(tenant string) {
+	c.tenantName = tenant
 }
 
-func Login(username, password string) error {
-	url := fmt.Sprintf("%s/login", BASE_URL)
+// Login authenticates with email and password and stores the JWT tokens of
+// every tenant the user belongs to. It fails if there is no token for the
+// client's tenant.
+func (c *Client) Login(username, password string) error {
+	return c.LoginContext(context.Background(), username, password)
+}
 
+// LoginContext is like Login but honors the cancellation and deadline of ctx
+func (c *Client) LoginContext(ctx context.Context, username, password string) error {
 	user := User{
 		Email:    username,
 		Password: password,
@@ -46,50 +140,87 @@ func Login(username, password string) error {
 		User: user,
 	}
 
-	requestBody, err := json.Marshal(loginRequest)
+	ctx = withOperation(ctx, "Login")
+	return c.storeTokens(c.requestTokens(ctx, "/login", loginRequest, ""))
+}
+
+// storeTokens caches the tokens of a login or refresh response and checks
+// that the client's tenant is among them
+func (c *Client) storeTokens(tokens map[string]string, err error) error {
+	if err != nil {
+		return err
+	}
+	c.tokens.setAll(tokens)
+
+	// Check the JWT token for the specified tenant is in the tokens map
+	if _, ok := tokens[c.tenantName]; !ok {
+		return fmt.Errorf("token for tenant %s not found in response", c.tenantName)
+	}
+	return nil
+}
+
+// requestTokens posts body to one of the token endpoints and returns the
+// tokens map of the response
+func (c *Client) requestTokens(ctx context.Context, path string, body interface{}, bearer string) (tokens map[string]string, err error) {
+	operation := OperationFromContext(ctx)
+	ctx, span := c.startSpan(ctx, operation, "POST", path)
+	start := time.Now()
+	var resp *http.Response
+	var requestBody []byte
+	defer func() {
+		latency := time.Since(start)
+		c.logCall(ctx, "POST", path, requestBody, resp, err, 0, latency)
+		c.recordRequest(operation, resp, err, latency)
+		endSpan(span, resp, 0, err)
+	}()
+
+	url := fmt.Sprintf("%s%s", c.baseURL, path)
+
+	requestBody, err = json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("error marshaling login request: %v", err)
+		return nil, fmt.Errorf("error marshaling login request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	injectTraceParent(ctx, req)
+	if bearer != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", bearer))
 	}
 
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	resp, err = c.handler(req)
 	if err != nil {
-		return fmt.Errorf("error making POST request: %v", err)
+		return nil, fmt.Errorf("error making POST request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("login request failed with status: %v", resp.Status)
+		return nil, newAPIError(resp)
 	}
 
 	var response loginResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return fmt.Errorf("error decoding login response: %v", err)
+		return nil, fmt.Errorf("error decoding login response: %w", err)
 	}
 
-	// Pick the JWT token for the specified tenant from the tokens map
-	token, ok := response.Tokens[TenantName]
-	if !ok {
-		return fmt.Errorf("token for tenant %s not found in response", TenantName)
-	}
-
-	mu.Lock()
-	jwtToken = token // Store the selected token globally
-	mu.Unlock()
-
-	return nil
+	return response.Tokens, nil
 }
 
-func GetJWT() (string, error) {
-	mu.Lock()
-	defer mu.Unlock()
-	if jwtToken == "" {
+// GetJWT returns the JWT token the client authenticates with
+func (c *Client) GetJWT() (string, error) {
+	token := c.tokens.get(c.tenantName).token
+	if token == "" {
 		return "", fmt.Errorf("JWT token not set")
 	}
-	return jwtToken, nil
+	return token, nil
 }
 
-func makeRequest(method, path string, body interface{}) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", BASE_URL, path)
+func (c *Client) makeRequest(ctx context.Context, operation, method, path string, body interface{}) (*http.Response, error) {
+	ctx = withOperation(ctx, operation)
+	url := fmt.Sprintf("%s%s", c.baseURL, path)
 
 	var requestBody []byte
 	var err error
@@ -103,34 +234,180 @@ func makeRequest(method, path string, body interface{}) (*http.Response, error)
 		requestBody = []byte{}
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+	idempotencyKey, err := c.idempotencyKey(ctx, method)
 	if err != nil {
-		return nil, fmt.Errorf("error creating new request: %v", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	mu.Lock()
-	if jwtToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwtToken))
+	header := http.Header{}
+	if idempotencyKey != "" {
+		header.Set(IdempotencyKeyHeader, idempotencyKey)
+	}
+
+	ctx, span := c.startSpan(ctx, operation, method, path)
+	start := time.Now()
+	var resp *http.Response
+	var retries int
+	if c.coalescingEnabled(ctx, method) {
+		resp, retries, err = c.sendCoalesced(ctx, url, header)
+	} else {
+		resp, retries, err = c.sendUncoalesced(ctx, method, url, requestBody, header)
+	}
+	if err == nil && c.cache != nil && isMutating(method) {
+		c.invalidateCache(path)
+	}
+	latency := time.Since(start)
+	c.logCall(ctx, method, path, requestBody, resp, err, retries, latency)
+	c.recordRequest(operation, resp, err, latency)
+	endSpan(span, resp, retries, err)
+	return resp, err
+}
+
+// sendUncoalesced makes a request through the response cache if it applies
+func (c *Client) sendUncoalesced(ctx context.Context, method, url string, requestBody []byte, header http.Header) (*http.Response, int, error) {
+	if c.cacheEnabled(ctx, method) {
+		return c.sendCached(ctx, url, header)
 	}
-	mu.Unlock()
+	return c.send(ctx, method, url, requestBody, header)
+}
+
+// send makes a request, retrying and re-authenticating as needed, and
+// returns the final response along with the number of retries made
+func (c *Client) send(ctx context.Context, method, url string, requestBody []byte, header http.Header) (*http.Response, int, error) {
+	policy := c.retryPolicy
+	idempotent := isIdempotent(method) || policy.keyedRetrySafe(ctx, header)
+	// A 304 answers a conditional request made by the response cache
+	conditional := header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""
 
-	resp, err := client.Do(req)
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		if err := c.waitRateLimit(ctx); err != nil {
+			return nil, attempt - 1, err
+		}
+		req, err := c.newRequest(ctx, method, url, requestBody)
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		var breaker *circuit
+		var generation uint64
+		if c.breakers != nil {
+			breaker = c.breakers.get(req.URL.Host, OperationFromContext(ctx))
+			if generation, err = breaker.allow(c.breakers.config); err != nil {
+				return nil, attempt - 1, err
+			}
+		}
+		resp, err := c.handler(req)
+		if breaker != nil {
+			if errors.Is(err, context.Canceled) {
+				breaker.release(generation)
+			} else {
+				breaker.record(c.breakers.config, generation, c.breakers.config.IsFailure(resp, err))
+			}
+		}
+		if err == nil {
+			c.observeRateLimit(resp)
+		}
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			if reauthenticator, ok := c.auth().(Reauthenticator); ok {
+				replay, err := reauthenticator.Reauthenticate(ctx, req)
+				if err != nil {
+					resp.Body.Close()
+					return nil, attempt - 1, err
+				}
+				if replay {
+					// Replay the request once without counting it as a retry
+					io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+					resp.Body.Close()
+					reauthenticated = true
+					attempt--
+					continue
+				}
+			}
+		}
+		if !policy.shouldRetry(ctx, idempotent, attempt, resp, err) {
+			if err != nil {
+				return nil, attempt - 1, fmt.Errorf("error making request: %w", err)
+			}
+			if (resp.StatusCode < 200 || resp.StatusCode > 299) && !(conditional && resp.StatusCode == http.StatusNotModified) {
+				defer resp.Body.Close()
+				return nil, attempt - 1, newAPIError(resp)
+			}
+			c.limitBody(resp)
+			return resp, attempt - 1, nil
+		}
+
+		delay, ok := policy.delay(attempt, resp)
+		if !ok {
+			defer resp.Body.Close()
+			return nil, attempt - 1, newAPIError(resp)
+		}
+
+		event := RetryEvent{
+			Method:  method,
+			Path:    req.URL.Path,
+			Attempt: attempt,
+			Err:     err,
+			Delay:   delay,
+		}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+			resp.Body.Close()
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(event)
+		}
+		c.logRetry(ctx, event)
+		if c.metrics != nil {
+			c.metrics.Retried(OperationFromContext(ctx))
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+// newRequest builds a single attempt of a request. The body is wrapped in a
+// fresh reader every time so that retries resend it in full.
+func (c *Client) newRequest(ctx context.Context, method, url string, requestBody []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(requestBody))
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return nil, fmt.Errorf("error creating new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	injectTraceParent(ctx, req)
+	if err := c.auth().Authenticate(ctx, req); err != nil {
+		return nil, err
 	}
 
-	return resp, nil
+	return req, nil
 }
 
-// Helper function to parse JSON responses
-func parseJSONResponse(response *http.Response, result interface{}) error {
+// Helper function to parse JSON responses. The body is read under the same
+// ctx as the request, so a cancellation while decoding aborts the read.
+func parseJSONResponse(ctx context.Context, response *http.Response, result interface{}) error {
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("error reading response: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	return json.Unmarshal(body, result)
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
 }
 
 // Helper function to format query parameters
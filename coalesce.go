@@ -0,0 +1,147 @@
+package goapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// CoalescingStats counts GET calls that went through request coalescing
+type CoalescingStats struct {
+	Calls  int64 // GET calls made
+	Shared int64 // Calls answered by another call's round trip
+}
+
+// WithRequestCoalescing merges concurrent identical GET calls (same tenant,
+// URL and credentials) into a single round trip. Every caller decodes its
+// own copy of the shared response, so results never alias each other.
+func WithRequestCoalescing() Option {
+	return func(c *Client) {
+		c.coalescer = &coalescer{flights: make(map[string]*flight)}
+	}
+}
+
+// CoalescingStats returns how many GET calls were made and how many of them
+// were saved by request coalescing
+func (c *Client) CoalescingStats() CoalescingStats {
+	if c.coalescer == nil {
+		return CoalescingStats{}
+	}
+	c.coalescer.mu.Lock()
+	defer c.coalescer.mu.Unlock()
+	return c.coalescer.stats
+}
+
+type coalescer struct {
+	mu      sync.Mutex // Mutex for thread-safe access to flights and stats
+	flights map[string]*flight
+	stats   CoalescingStats
+}
+
+// flight is a GET request shared by every caller waiting on it
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	status  int
+	header  http.Header
+	body    []byte
+	retries int
+	err     error
+}
+
+func (c *Client) coalescingEnabled(ctx context.Context, method string) bool {
+	return c.coalescer != nil && method == http.MethodGet && !isStreaming(ctx)
+}
+
+// coalescingKey identifies identical requests: the same URL for the same
+// tenant sent with the same credentials
+func (c *Client) coalescingKey(ctx context.Context, url string) (string, error) {
+	credentials, err := c.credentialKey(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return c.tenantName + " " + url + " " + credentials, nil
+}
+
+// sendCoalesced makes a GET request, joining an identical one in flight if
+// there is one. The shared request runs with the values of the first
+// caller's ctx, which carry the same credentials as every caller joining it,
+// and is canceled only once every caller waiting on it has given up.
+func (c *Client) sendCoalesced(ctx context.Context, url string, header http.Header) (*http.Response, int, error) {
+	key, err := c.coalescingKey(ctx, url)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c.coalescer.mu.Lock()
+	c.coalescer.stats.Calls++
+	f, ok := c.coalescer.flights[key]
+	if ok {
+		c.coalescer.stats.Shared++
+	} else {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		c.coalescer.flights[key] = f
+		go c.fly(flightCtx, key, f, url, header)
+	}
+	f.waiters++
+	c.coalescer.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		c.coalescer.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Later callers must not join a flight that is being canceled
+			if c.coalescer.flights[key] == f {
+				delete(c.coalescer.flights, key)
+			}
+			f.cancel()
+		}
+		c.coalescer.mu.Unlock()
+		return nil, 0, ctx.Err()
+	}
+
+	if f.err != nil {
+		return nil, f.retries, f.err
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", f.status, http.StatusText(f.status)),
+		StatusCode: f.status,
+		Header:     f.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(f.body)),
+	}, f.retries, nil
+}
+
+// fly performs the shared request of f and reads its body for all callers
+func (c *Client) fly(ctx context.Context, key string, f *flight, url string, header http.Header) {
+	defer func() {
+		c.coalescer.mu.Lock()
+		if c.coalescer.flights[key] == f {
+			delete(c.coalescer.flights, key)
+		}
+		c.coalescer.mu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+
+	resp, retries, err := c.sendUncoalesced(ctx, http.MethodGet, url, nil, header)
+	f.retries = retries
+	if err != nil {
+		f.err = err
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		f.err = fmt.Errorf("error reading response: %w", err)
+		return
+	}
+	f.status, f.header, f.body = resp.StatusCode, resp.Header, body
+}
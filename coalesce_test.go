@@ -0,0 +1,147 @@
+package goapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+)
+
+func TestCoalescingAbandonedFlightIsNotJoined(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":"u1"}]`))
+	}))
+	defer srv.Close()
+
+	// The first round trip is slow to notice its cancellation
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	slow := func(next goapi.Handler) goapi.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				close(entered)
+				<-release
+				return nil, req.Context().Err()
+			}
+			return next(req)
+		}
+	}
+	client := goapi.New(srv.URL,
+		goapi.WithRequestCoalescing(),
+		goapi.WithMiddleware(slow),
+		goapi.WithRetryPolicy(goapi.NoRetries()),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.GetUsersContext(ctx)
+		errs <- err
+	}()
+	<-entered
+	cancel()
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceled call did not return")
+	}
+	// Let the abandoned round trip finish while the next call is made
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatalf("GetUsers joined the abandoned flight: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != "u1" {
+		t.Fatalf("users = %+v", users)
+	}
+}
+
+func TestCoalescingSharesConcurrentCalls(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	client := goapi.New(srv.URL, goapi.WithRequestCoalescing())
+
+	const callers = 5
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := client.GetUsers()
+			errs <- err
+		}()
+	}
+	for client.CoalescingStats().Calls < callers {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	for i := 0; i < callers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1", got)
+	}
+	if stats := client.CoalescingStats(); stats.Shared != callers-1 {
+		t.Errorf("stats = %+v, want %d shared", stats, callers-1)
+	}
+}
+
+type userKey struct{}
+
+func TestCoalescingKeepsUsersApart(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprintf(w, `[{"id":%q}]`, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer srv.Close()
+	// A gateway forwards the JWT of each of its own callers
+	perUser := goapi.TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return ctx.Value(userKey{}).(string), nil
+	})
+	client := goapi.New(srv.URL, goapi.WithRequestCoalescing(), goapi.WithTokenSource(perUser))
+
+	callers := []string{"alice", "bob", "alice"}
+	results := make([]string, len(callers))
+	errs := make(chan error, len(callers))
+	for i, user := range callers {
+		go func() {
+			users, err := client.GetUsersContext(context.WithValue(context.Background(), userKey{}, user))
+			if err == nil {
+				results[i] = users[0].ID
+			}
+			errs <- err
+		}()
+	}
+	for client.CoalescingStats().Calls < int64(len(callers)) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	for range callers {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(results, callers) {
+		t.Errorf("results = %q, want %q", results, callers)
+	}
+	if stats := client.CoalescingStats(); stats.Shared != 1 {
+		t.Errorf("stats = %+v, want 1 shared", stats)
+	}
+}
@@ -0,0 +1,332 @@
+package goapi
+
+import "context"
+
+// The package-level functions below operate on a shared default Client so
+// that code written before Client existed keeps working. New code should
+// create its own Client with New.
+
+var defaultClient = New("")
+
+// Default returns the Client used by the package-level functions
+func Default() *Client {
+	return defaultClient
+}
+
+// SetBaseURL sets the API base URL of the default client
+func SetBaseURL(url string) {
+	defaultClient.SetBaseURL(url)
+}
+
+// SetTenantName sets the tenant of the default client
+func SetTenantName(tenant string) {
+	defaultClient.SetTenantName(tenant)
+}
+
+// Tenants returns the names of the tenants the default client holds tokens
+// for
+func Tenants() []string {
+	return defaultClient.Tenants()
+}
+
+// ForTenant returns a view of the default client scoped to another tenant
+func ForTenant(tenant string) *Client {
+	return defaultClient.ForTenant(tenant)
+}
+
+// BaseURL returns the API base URL the default client sends requests to
+func BaseURL() string {
+	return defaultClient.BaseURL()
+}
+
+// TenantName returns the tenant whose token the default client uses
+func TenantName() string {
+	return defaultClient.TenantName()
+}
+
+// Login authenticates with email and password and stores the JWT tokens of
+// every tenant the user belongs to. It fails if there is no token for the
+// client's tenant.
+func Login(username, password string) error {
+	return defaultClient.Login(username, password)
+}
+
+// LoginContext is like Login but honors the cancellation and deadline of ctx
+func LoginContext(ctx context.Context, username, password string) error {
+	return defaultClient.LoginContext(ctx, username, password)
+}
+
+// GetJWT returns the JWT token the default client authenticates with
+func GetJWT() (string, error) {
+	return defaultClient.GetJWT()
+}
+
+// GetUsers retrieves a list of users
+func GetUsers() ([]User, error) {
+	return defaultClient.GetUsers()
+}
+
+// GetUsersContext is like GetUsers but honors the cancellation and deadline of ctx
+func GetUsersContext(ctx context.Context) ([]User, error) {
+	return defaultClient.GetUsersContext(ctx)
+}
+
+// GetUser retrieves a single user by ID
+func GetUser(userID string) (*User, error) {
+	return defaultClient.GetUser(userID)
+}
+
+// GetUserContext is like GetUser but honors the cancellation and deadline of ctx
+func GetUserContext(ctx context.Context, userID string) (*User, error) {
+	return defaultClient.GetUserContext(ctx, userID)
+}
+
+// CreateUser creates a new user
+func CreateUser(user User) (*User, error) {
+	return defaultClient.CreateUser(user)
+}
+
+// CreateUserContext is like CreateUser but honors the cancellation and deadline of ctx
+func CreateUserContext(ctx context.Context, user User) (*User, error) {
+	return defaultClient.CreateUserContext(ctx, user)
+}
+
+// DeleteUser deletes a user by ID
+func DeleteUser(userID string) error {
+	return defaultClient.DeleteUser(userID)
+}
+
+// DeleteUserContext is like DeleteUser but honors the cancellation and deadline of ctx
+func DeleteUserContext(ctx context.Context, userID string) error {
+	return defaultClient.DeleteUserContext(ctx, userID)
+}
+
+// UpdateUser updates an existing user
+func UpdateUser(userID string, user User) (*User, error) {
+	return defaultClient.UpdateUser(userID, user)
+}
+
+// UpdateUserContext is like UpdateUser but honors the cancellation and deadline of ctx
+func UpdateUserContext(ctx context.Context, userID string, user User) (*User, error) {
+	return defaultClient.UpdateUserContext(ctx, userID, user)
+}
+
+// GetTimeSheets retrieves time sheets for a specific user profile
+func GetTimeSheets(userProfileID string) ([]ProfileTimeSheet, error) {
+	return defaultClient.GetTimeSheets(userProfileID)
+}
+
+// GetTimeSheetsContext is like GetTimeSheets but honors the cancellation and deadline of ctx
+func GetTimeSheetsContext(ctx context.Context, userProfileID string) ([]ProfileTimeSheet, error) {
+	return defaultClient.GetTimeSheetsContext(ctx, userProfileID)
+}
+
+// CreateTimeSheet creates a new time sheet
+func CreateTimeSheet(timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.CreateTimeSheet(timeSheet)
+}
+
+// CreateTimeSheetContext is like CreateTimeSheet but honors the cancellation and deadline of ctx
+func CreateTimeSheetContext(ctx context.Context, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.CreateTimeSheetContext(ctx, timeSheet)
+}
+
+// UpdateTimeSheet updates an existing time sheet
+func UpdateTimeSheet(timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.UpdateTimeSheet(timeSheetID, timeSheet)
+}
+
+// UpdateTimeSheetContext is like UpdateTimeSheet but honors the cancellation and deadline of ctx
+func UpdateTimeSheetContext(ctx context.Context, timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.UpdateTimeSheetContext(ctx, timeSheetID, timeSheet)
+}
+
+// DeleteTimeSheet deletes a time sheet by ID
+func DeleteTimeSheet(timeSheetID string) error {
+	return defaultClient.DeleteTimeSheet(timeSheetID)
+}
+
+// DeleteTimeSheetContext is like DeleteTimeSheet but honors the cancellation and deadline of ctx
+func DeleteTimeSheetContext(ctx context.Context, timeSheetID string) error {
+	return defaultClient.DeleteTimeSheetContext(ctx, timeSheetID)
+}
+
+// GetReimbursements retrieves reimbursements for a specific user profile
+func GetReimbursements(userProfileID string) ([]ProfileReimbursement, error) {
+	return defaultClient.GetReimbursements(userProfileID)
+}
+
+// GetReimbursementsContext is like GetReimbursements but honors the cancellation and deadline of ctx
+func GetReimbursementsContext(ctx context.Context, userProfileID string) ([]ProfileReimbursement, error) {
+	return defaultClient.GetReimbursementsContext(ctx, userProfileID)
+}
+
+// CreateReimbursement creates a new reimbursement
+func CreateReimbursement(reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+	return defaultClient.CreateReimbursement(reimbursement)
+}
+
+// CreateReimbursementContext is like CreateReimbursement but honors the cancellation and deadline of ctx
+func CreateReimbursementContext(ctx context.Context, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+	return defaultClient.CreateReimbursementContext(ctx, reimbursement)
+}
+
+// UpdateReimbursement updates an existing reimbursement
+func UpdateReimbursement(reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+	return defaultClient.UpdateReimbursement(reimbursementID, reimbursement)
+}
+
+// UpdateReimbursementContext is like UpdateReimbursement but honors the cancellation and deadline of ctx
+func UpdateReimbursementContext(ctx context.Context, reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+	return defaultClient.UpdateReimbursementContext(ctx, reimbursementID, reimbursement)
+}
+
+// DeleteReimbursement deletes a reimbursement by ID
+func DeleteReimbursement(reimbursementID string) error {
+	return defaultClient.DeleteReimbursement(reimbursementID)
+}
+
+// DeleteReimbursementContext is like DeleteReimbursement but honors the cancellation and deadline of ctx
+func DeleteReimbursementContext(ctx context.Context, reimbursementID string) error {
+	return defaultClient.DeleteReimbursementContext(ctx, reimbursementID)
+}
+
+// ClockIn records a clock-in for a specific user profile
+func ClockIn(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.ClockIn(userProfileID, timeSheet)
+}
+
+// ClockInContext is like ClockIn but honors the cancellation and deadline of ctx
+func ClockInContext(ctx context.Context, userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.ClockInContext(ctx, userProfileID, timeSheet)
+}
+
+// ClockOut records a clock-out for a specific user profile
+func ClockOut(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.ClockOut(userProfileID, timeSheet)
+}
+
+// ClockOutContext is like ClockOut but honors the cancellation and deadline of ctx
+func ClockOutContext(ctx context.Context, userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return defaultClient.ClockOutContext(ctx, userProfileID, timeSheet)
+}
+
+// GetProducts retrieves a list of products
+func GetProducts() ([]Product, error) {
+	return defaultClient.GetProducts()
+}
+
+// GetProductsContext is like GetProducts but honors the cancellation and deadline of ctx
+func GetProductsContext(ctx context.Context) ([]Product, error) {
+	return defaultClient.GetProductsContext(ctx)
+}
+
+// FilterProducts retrieves a list of products with the specified filters
+func FilterProducts(filters map[string]string) ([]Product, error) {
+	return defaultClient.FilterProducts(filters)
+}
+
+// FilterProductsContext is like FilterProducts but honors the cancellation and deadline of ctx
+func FilterProductsContext(ctx context.Context, filters map[string]string) ([]Product, error) {
+	return defaultClient.FilterProductsContext(ctx, filters)
+}
+
+// CreateProduct creates a new product
+func CreateProduct(product Product) (*Product, error) {
+	return defaultClient.CreateProduct(product)
+}
+
+// CreateProductContext is like CreateProduct but honors the cancellation and deadline of ctx
+func CreateProductContext(ctx context.Context, product Product) (*Product, error) {
+	return defaultClient.CreateProductContext(ctx, product)
+}
+
+// GetProduct retrieves a single product by ID
+func GetProduct(productID string) (*Product, error) {
+	return defaultClient.GetProduct(productID)
+}
+
+// GetProductContext is like GetProduct but honors the cancellation and deadline of ctx
+func GetProductContext(ctx context.Context, productID string) (*Product, error) {
+	return defaultClient.GetProductContext(ctx, productID)
+}
+
+// UpdateProduct updates an existing product
+func UpdateProduct(productID string, product Product) (*Product, error) {
+	return defaultClient.UpdateProduct(productID, product)
+}
+
+// UpdateProductContext is like UpdateProduct but honors the cancellation and deadline of ctx
+func UpdateProductContext(ctx context.Context, productID string, product Product) (*Product, error) {
+	return defaultClient.UpdateProductContext(ctx, productID, product)
+}
+
+// DeleteProduct deletes a product by ID
+func DeleteProduct(productID string) error {
+	return defaultClient.DeleteProduct(productID)
+}
+
+// DeleteProductContext is like DeleteProduct but honors the cancellation and deadline of ctx
+func DeleteProductContext(ctx context.Context, productID string) error {
+	return defaultClient.DeleteProductContext(ctx, productID)
+}
+
+// CreateProductSchedule creates a new product schedule
+func CreateProductSchedule(schedule ProductSchedule) (*ProductSchedule, error) {
+	return defaultClient.CreateProductSchedule(schedule)
+}
+
+// CreateProductScheduleContext is like CreateProductSchedule but honors the cancellation and deadline of ctx
+func CreateProductScheduleContext(ctx context.Context, schedule ProductSchedule) (*ProductSchedule, error) {
+	return defaultClient.CreateProductScheduleContext(ctx, schedule)
+}
+
+// GetProductSchedule retrieves a product schedule by ID
+func GetProductSchedule(scheduleID string) (*ProductSchedule, error) {
+	return defaultClient.GetProductSchedule(scheduleID)
+}
+
+// GetProductScheduleContext is like GetProductSchedule but honors the cancellation and deadline of ctx
+func GetProductScheduleContext(ctx context.Context, scheduleID string) (*ProductSchedule, error) {
+	return defaultClient.GetProductScheduleContext(ctx, scheduleID)
+}
+
+// CreateProductScheduleSession creates a new product schedule session
+func CreateProductScheduleSession(session ProductScheduleSession) (*ProductScheduleSession, error) {
+	return defaultClient.CreateProductScheduleSession(session)
+}
+
+// CreateProductScheduleSessionContext is like CreateProductScheduleSession but honors the cancellation and deadline of ctx
+func CreateProductScheduleSessionContext(ctx context.Context, session ProductScheduleSession) (*ProductScheduleSession, error) {
+	return defaultClient.CreateProductScheduleSessionContext(ctx, session)
+}
+
+// GetProductScheduleSession retrieves a product schedule session by ID
+func GetProductScheduleSession(sessionID string) (*ProductScheduleSession, error) {
+	return defaultClient.GetProductScheduleSession(sessionID)
+}
+
+// GetProductScheduleSessionContext is like GetProductScheduleSession but honors the cancellation and deadline of ctx
+func GetProductScheduleSessionContext(ctx context.Context, sessionID string) (*ProductScheduleSession, error) {
+	return defaultClient.GetProductScheduleSessionContext(ctx, sessionID)
+}
+
+// CreateProductScheduleSessionUser creates a new product schedule session user
+func CreateProductScheduleSessionUser(sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error) {
+	return defaultClient.CreateProductScheduleSessionUser(sessionUser)
+}
+
+// CreateProductScheduleSessionUserContext is like CreateProductScheduleSessionUser but honors the cancellation and deadline of ctx
+func CreateProductScheduleSessionUserContext(ctx context.Context, sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error) {
+	return defaultClient.CreateProductScheduleSessionUserContext(ctx, sessionUser)
+}
+
+// CreateProductScheduleSessionResource creates a new product schedule session resource
+func CreateProductScheduleSessionResource(sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error) {
+	return defaultClient.CreateProductScheduleSessionResource(sessionResource)
+}
+
+// CreateProductScheduleSessionResourceContext is like CreateProductScheduleSessionResource but honors the cancellation and deadline of ctx
+func CreateProductScheduleSessionResourceContext(ctx context.Context, sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error) {
+	return defaultClient.CreateProductScheduleSessionResourceContext(ctx, sessionResource)
+}
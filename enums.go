@@ -0,0 +1,146 @@
+package goapi
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// The enum types below are strings so that values added by newer servers
+// survive a round trip. Decoding trims whitespace and matches the known
+// values case-insensitively; anything else is kept as is and reported by
+// IsValid.
+
+// ReimbursementStatus is the review state of a reimbursement
+type ReimbursementStatus string
+
+const (
+	ReimbursementStatusPending  ReimbursementStatus = "pending"
+	ReimbursementStatusApproved ReimbursementStatus = "approved"
+	ReimbursementStatusRejected ReimbursementStatus = "rejected"
+	ReimbursementStatusPaid     ReimbursementStatus = "paid"
+)
+
+var reimbursementStatuses = []ReimbursementStatus{
+	ReimbursementStatusPending,
+	ReimbursementStatusApproved,
+	ReimbursementStatusRejected,
+	ReimbursementStatusPaid,
+}
+
+// IsValid reports whether s is one of the known statuses
+func (s ReimbursementStatus) IsValid() bool {
+	return isKnown(s, reimbursementStatuses)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *ReimbursementStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, reimbursementStatuses)
+}
+
+// Attendance is the attendance of a subscriber at a session instance. The
+// API does not document its values, so they are kept exactly as sent.
+type Attendance string
+
+// Weekday is the day of the week a session takes place on
+type Weekday string
+
+const (
+	WeekdaySunday    Weekday = "Sunday"
+	WeekdayMonday    Weekday = "Monday"
+	WeekdayTuesday   Weekday = "Tuesday"
+	WeekdayWednesday Weekday = "Wednesday"
+	WeekdayThursday  Weekday = "Thursday"
+	WeekdayFriday    Weekday = "Friday"
+	WeekdaySaturday  Weekday = "Saturday"
+)
+
+// weekdays is indexed by time.Weekday
+var weekdays = []Weekday{
+	WeekdaySunday,
+	WeekdayMonday,
+	WeekdayTuesday,
+	WeekdayWednesday,
+	WeekdayThursday,
+	WeekdayFriday,
+	WeekdaySaturday,
+}
+
+// WeekdayOf returns the Weekday of d
+func WeekdayOf(d time.Weekday) Weekday {
+	if d < time.Sunday || d > time.Saturday {
+		return Weekday(d.String())
+	}
+	return weekdays[d]
+}
+
+// Weekday returns w as a time.Weekday. ok is false if w is not valid.
+func (w Weekday) Weekday() (day time.Weekday, ok bool) {
+	for i, known := range weekdays {
+		if w == known {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// IsValid reports whether w is one of the seven days
+func (w Weekday) IsValid() bool {
+	return isKnown(w, weekdays)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (w *Weekday) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, w, weekdays)
+}
+
+// StaffRole is the role of a staff member in a session
+type StaffRole string
+
+// StaffRoleInstructor is the only role the API is known to use. Other roles
+// decode as is.
+const StaffRoleInstructor StaffRole = "Instructor"
+
+var staffRoles = []StaffRole{
+	StaffRoleInstructor,
+}
+
+// IsValid reports whether r is one of the known roles
+func (r StaffRole) IsValid() bool {
+	return isKnown(r, staffRoles)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *StaffRole) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, r, staffRoles)
+}
+
+func isKnown[T ~string](value T, known []T) bool {
+	for _, k := range known {
+		if value == k {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalEnum decodes a JSON string into value, normalizing it to one of
+// known if it matches one up to case and surrounding whitespace
+func unmarshalEnum[T ~string](data []byte, value *T, known []T) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	for _, k := range known {
+		if strings.EqualFold(trimmed, string(k)) {
+			*value = k
+			return nil
+		}
+	}
+	*value = T(*s)
+	return nil
+}
@@ -0,0 +1,106 @@
+package goapi_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+)
+
+func TestEnumUnmarshal(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		target    interface{ IsValid() bool }
+		want      string
+		wantValid bool
+	}{
+		{name: "status", json: `"approved"`, target: new(goapi.ReimbursementStatus), want: "approved", wantValid: true},
+		{name: "status case and whitespace", json: `" Approved\n"`, target: new(goapi.ReimbursementStatus), want: "approved", wantValid: true},
+		{name: "status typo", json: `"aproved"`, target: new(goapi.ReimbursementStatus), want: "aproved"},
+		{name: "status from a newer server", json: `"on_hold"`, target: new(goapi.ReimbursementStatus), want: "on_hold"},
+		{name: "weekday trailing space", json: `"Monday "`, target: new(goapi.Weekday), want: "Monday", wantValid: true},
+		{name: "weekday lower case", json: `"saturday"`, target: new(goapi.Weekday), want: "Saturday", wantValid: true},
+		{name: "weekday typo", json: `"Munday"`, target: new(goapi.Weekday), want: "Munday"},
+		{name: "unknown weekday keeps whitespace", json: `" Funday "`, target: new(goapi.Weekday), want: " Funday "},
+		{name: "role", json: `"INSTRUCTOR"`, target: new(goapi.StaffRole), want: "Instructor", wantValid: true},
+		{name: "role from a newer server", json: `"Assistant"`, target: new(goapi.StaffRole), want: "Assistant"},
+		{name: "null", json: `null`, target: new(goapi.Weekday), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.json), tt.target); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotString string
+			json.Unmarshal(got, &gotString)
+			if gotString != tt.want {
+				t.Errorf("decoded %s as %s, want %q", tt.json, got, tt.want)
+			}
+			if valid := tt.target.IsValid(); valid != tt.wantValid {
+				t.Errorf("IsValid() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+
+	var day goapi.Weekday
+	if err := json.Unmarshal([]byte(`1`), &day); err == nil {
+		t.Errorf("decoding a number gave %q, want an error", day)
+	}
+}
+
+func TestEnumFields(t *testing.T) {
+	var session goapi.ProductScheduleSession
+	if err := json.Unmarshal([]byte(`{"day": "friday ", "staff": [{"role": " instructor"}]}`), &session); err != nil {
+		t.Fatal(err)
+	}
+	if session.Day != goapi.WeekdayFriday || session.Staff[0].Role != goapi.StaffRoleInstructor {
+		t.Errorf("session = %+v, want Friday with an Instructor", session)
+	}
+
+	// Attendance has no documented values and is kept exactly as sent
+	var attendance goapi.ProductScheduleSessionInstanceAttendance
+	if err := json.Unmarshal([]byte(`{"attendance": " Present"}`), &attendance); err != nil {
+		t.Fatal(err)
+	}
+	if attendance.Attendance != " Present" {
+		t.Errorf("attendance = %q, want it unchanged", attendance.Attendance)
+	}
+}
+
+func TestWeekdayOf(t *testing.T) {
+	want := []goapi.Weekday{
+		goapi.WeekdaySunday,
+		goapi.WeekdayMonday,
+		goapi.WeekdayTuesday,
+		goapi.WeekdayWednesday,
+		goapi.WeekdayThursday,
+		goapi.WeekdayFriday,
+		goapi.WeekdaySaturday,
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		day := goapi.WeekdayOf(d)
+		if day != want[d] || string(day) != d.String() {
+			t.Errorf("WeekdayOf(%v) = %q, want %q", d, day, want[d])
+		}
+		if back, ok := day.Weekday(); !ok || back != d {
+			t.Errorf("%q.Weekday() = %v, %v; want %v", day, back, ok, d)
+		}
+	}
+
+	if day := goapi.WeekdayOf(time.Weekday(9)); day.IsValid() {
+		t.Errorf("WeekdayOf(9) = %q, want an invalid weekday", day)
+	}
+	if _, ok := goapi.Weekday("Munday").Weekday(); ok {
+		t.Error("Munday converted to a time.Weekday")
+	}
+	// Only the canonical spelling converts; decoding normalizes the rest
+	if _, ok := goapi.Weekday("monday").Weekday(); ok {
+		t.Error("monday converted to a time.Weekday")
+	}
+}
@@ -0,0 +1,197 @@
+package goapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError through errors.Is
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// maxErrorBodySize caps how much of an error response body is kept
+const maxErrorBodySize = 64 << 10
+
+// FieldError describes a problem with a single field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// APIError is returned for every response with a non-2xx status code
+type APIError struct {
+	StatusCode  int
+	Method      string
+	Path        string
+	Code        string       // Error code reported by the server, if any
+	Message     string       // Error message reported by the server, if any
+	RequestID   string       // Request ID from the response headers or body
+	FieldErrors []FieldError // Field-level validation details, if any
+	Body        []byte       // Raw response body, truncated to 64KB
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s failed with status %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, " (code %s)", e.Code)
+	}
+	for i, fe := range e.FieldErrors {
+		if i == 0 {
+			b.WriteString(" [")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(fe.Error())
+		if i == len(e.FieldErrors)-1 {
+			b.WriteString("]")
+		}
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Is reports whether the error's status code corresponds to target
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity ||
+			(e.StatusCode == http.StatusBadRequest && len(e.FieldErrors) > 0)
+	}
+	return false
+}
+
+// errorResponse covers the error body shapes the API is known to return:
+// {"error": "..."}, {"error": {"code": "...", "message": "..."}} and
+// {"message": "...", "errors": {...}}
+type errorResponse struct {
+	Error     json.RawMessage `json:"error"`
+	Code      string          `json:"code"`
+	Message   string          `json:"message"`
+	RequestID string          `json:"request_id"`
+	Errors    json.RawMessage `json:"errors"`
+}
+
+// newAPIError builds an *APIError from a failed response, consuming its body
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  requestIDFromHeader(resp.Header),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.Path
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	apiErr.Body = body
+
+	var parsed errorResponse
+	if len(body) > 0 && json.Unmarshal(body, &parsed) == nil {
+		apiErr.Code = parsed.Code
+		apiErr.Message = parsed.Message
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = parsed.RequestID
+		}
+		if len(parsed.Error) > 0 {
+			var msg string
+			var nested errorResponse
+			if json.Unmarshal(parsed.Error, &msg) == nil {
+				if apiErr.Message == "" {
+					apiErr.Message = msg
+				}
+			} else if json.Unmarshal(parsed.Error, &nested) == nil {
+				if nested.Code != "" {
+					apiErr.Code = nested.Code
+				}
+				if nested.Message != "" {
+					apiErr.Message = nested.Message
+				}
+				if len(parsed.Errors) == 0 {
+					parsed.Errors = nested.Errors
+				}
+			}
+		}
+		apiErr.FieldErrors = parseFieldErrors(parsed.Errors)
+	} else if len(body) > 0 && !strings.HasPrefix(strings.TrimSpace(string(body)), "<") {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// parseFieldErrors accepts either a list of {field, code, message} objects
+// or a map of field name to one or more messages
+func parseFieldErrors(raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var list []FieldError
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+
+	var byField map[string]json.RawMessage
+	if json.Unmarshal(raw, &byField) != nil {
+		return nil
+	}
+	var fieldErrors []FieldError
+	for field, value := range byField {
+		var messages []string
+		var message string
+		if json.Unmarshal(value, &messages) == nil {
+			for _, m := range messages {
+				fieldErrors = append(fieldErrors, FieldError{Field: field, Message: m})
+			}
+		} else if json.Unmarshal(value, &message) == nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: field, Message: message})
+		}
+	}
+	sort.SliceStable(fieldErrors, func(i, j int) bool {
+		return fieldErrors[i].Field < fieldErrors[j].Field
+	})
+	return fieldErrors
+}
+
+func requestIDFromHeader(header http.Header) string {
+	for _, key := range []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"} {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
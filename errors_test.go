@@ -0,0 +1,162 @@
+package goapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		header      http.Header
+		body        string
+		wantCode    string
+		wantMessage string
+		wantID      string
+		wantFields  []FieldError
+	}{
+		{
+			name:        "error string",
+			status:      http.StatusNotFound,
+			body:        `{"error": "user not found"}`,
+			wantMessage: "user not found",
+		},
+		{
+			name:        "nested error object",
+			status:      http.StatusConflict,
+			body:        `{"error": {"code": "already_clocked_in", "message": "profile is clocked in", "errors": [{"field": "user_profile_id", "code": "busy", "message": "is busy"}]}, "request_id": "req-body"}`,
+			wantCode:    "already_clocked_in",
+			wantMessage: "profile is clocked in",
+			wantID:      "req-body",
+			wantFields:  []FieldError{{Field: "user_profile_id", Code: "busy", Message: "is busy"}},
+		},
+		{
+			name:        "message with field error list",
+			status:      http.StatusUnprocessableEntity,
+			body:        `{"code": "invalid", "message": "validation failed", "errors": [{"field": "name", "code": "required", "message": "is required"}, {"field": "email", "message": "is invalid"}]}`,
+			wantCode:    "invalid",
+			wantMessage: "validation failed",
+			wantFields: []FieldError{
+				{Field: "name", Code: "required", Message: "is required"},
+				{Field: "email", Message: "is invalid"},
+			},
+		},
+		{
+			name:        "message with field error map",
+			status:      http.StatusBadRequest,
+			body:        `{"message": "bad request", "errors": {"name": ["is required", "is too short"], "email": "is invalid", "age": 3}}`,
+			wantMessage: "bad request",
+			wantFields: []FieldError{
+				{Field: "email", Message: "is invalid"},
+				{Field: "name", Message: "is required"},
+				{Field: "name", Message: "is too short"},
+			},
+		},
+		{
+			name:        "request ID header wins over body",
+			status:      http.StatusInternalServerError,
+			header:      http.Header{"X-Request-Id": {"req-header"}},
+			body:        `{"message": "boom", "request_id": "req-body"}`,
+			wantMessage: "boom",
+			wantID:      "req-header",
+		},
+		{
+			name:        "correlation ID header",
+			status:      http.StatusBadGateway,
+			header:      http.Header{"X-Correlation-Id": {"corr-1"}},
+			wantMessage: "Bad Gateway",
+			wantID:      "corr-1",
+		},
+		{
+			name:        "plain text body",
+			status:      http.StatusServiceUnavailable,
+			body:        "  down for maintenance\n",
+			wantMessage: "down for maintenance",
+		},
+		{
+			name:        "HTML body",
+			status:      http.StatusBadGateway,
+			body:        "<html><body>Bad gateway</body></html>",
+			wantMessage: "Bad Gateway",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+				Request:    &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/users"}},
+			}
+			apiErr := newAPIError(resp)
+
+			if apiErr.StatusCode != tt.status || apiErr.Method != http.MethodPost || apiErr.Path != "/users" {
+				t.Errorf("request = %d %s %s", apiErr.StatusCode, apiErr.Method, apiErr.Path)
+			}
+			if apiErr.Code != tt.wantCode || apiErr.Message != tt.wantMessage || apiErr.RequestID != tt.wantID {
+				t.Errorf("code, message, request ID = %q, %q, %q; want %q, %q, %q",
+					apiErr.Code, apiErr.Message, apiErr.RequestID, tt.wantCode, tt.wantMessage, tt.wantID)
+			}
+			if !reflect.DeepEqual(apiErr.FieldErrors, tt.wantFields) {
+				t.Errorf("field errors = %+v, want %+v", apiErr.FieldErrors, tt.wantFields)
+			}
+			if string(apiErr.Body) != tt.body {
+				t.Errorf("body = %q, want %q", apiErr.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrConflict, ErrValidation}
+	tests := []struct {
+		status int
+		fields []FieldError
+		want   error // nil for none
+	}{
+		{status: http.StatusNotFound, want: ErrNotFound},
+		{status: http.StatusUnauthorized, want: ErrUnauthorized},
+		{status: http.StatusForbidden, want: ErrForbidden},
+		{status: http.StatusConflict, want: ErrConflict},
+		{status: http.StatusUnprocessableEntity, want: ErrValidation},
+		{status: http.StatusBadRequest, fields: []FieldError{{Field: "name"}}, want: ErrValidation},
+		{status: http.StatusBadRequest},
+		{status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		err := error(&APIError{StatusCode: tt.status, FieldErrors: tt.fields})
+		wrapped := errors.Join(errors.New("context"), err)
+		for _, sentinel := range sentinels {
+			want := sentinel == tt.want
+			if got := errors.Is(wrapped, sentinel); got != want {
+				t.Errorf("status %d with %d field errors: errors.Is(%v) = %v, want %v", tt.status, len(tt.fields), sentinel, got, want)
+			}
+		}
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	err := &APIError{
+		StatusCode:  http.StatusUnprocessableEntity,
+		Method:      http.MethodPost,
+		Path:        "/users",
+		Code:        "invalid",
+		Message:     "validation failed",
+		RequestID:   "req-1",
+		FieldErrors: []FieldError{{Field: "name", Message: "is required"}, {Message: "too many users"}},
+	}
+	want := "POST /users failed with status 422: validation failed (code invalid) [name: is required; too many users] (request id req-1)"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"log"
 	"time"
 
-	"goapi"
+	"github.com/classify-api/goapi"
 )
 
 func handle() {
@@ -57,8 +57,8 @@ func handle() {
 	productSchedule := goapi.ProductSchedule{
 		TenantID:  "tenant123",
 		ProductID: "product123",
-		BeginDate: time.Now().Format("2006-01-02"),
-		EndDate:   time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		BeginDate: goapi.DateOf(time.Now()),
+		EndDate:   goapi.DateOf(time.Now().AddDate(0, 1, 0)),
 		TimeZone:  "America/New_York",
 		Sessions: []goapi.ProductScheduleSession{
 			{
@@ -66,16 +66,15 @@ func handle() {
 				TenantID:          "tenant123",
 				ProductScheduleID: "schedule123",
 				LocationID:        "location123",
-				Day:               "Monday",
-				BeginTime:         "09:00",
+				Day:               goapi.WeekdayMonday,
+				BeginTime:         &goapi.TimeOfDay{Hour: 9},
 				DurationMinutes:   60,
 				Staff: []goapi.ProductScheduleSessionUser{
 					{
 						TenantID:                 "tenant123",
 						ProductScheduleSessionID: "session123",
 						UserID:                   "user123",
-						User:                     &goapi.User{ID: "user123", Name: "John Doe"},
-						Role:                     "Instructor",
+						Role:                     goapi.StaffRoleInstructor,
 					},
 				},
 				Resources: []goapi.ProductScheduleSessionResource{
@@ -83,7 +82,7 @@ func handle() {
 						TenantID:                 "tenant123",
 						ProductScheduleSessionID: "session123",
 						ResourceID:               "resource123",
-						BeginTime:                "09:00",
+						BeginTime:                &goapi.TimeOfDay{Hour: 9},
 						DurationMinutes:          60,
 					},
 				},
@@ -111,16 +110,15 @@ func handle() {
 		TenantID:          "tenant123",
 		ProductScheduleID: "schedule123",
 		LocationID:        "location123",
-		Day:               "Monday",
-		BeginTime:         "09:00",
+		Day:               goapi.WeekdayMonday,
+		BeginTime:         &goapi.TimeOfDay{Hour: 9},
 		DurationMinutes:   60,
 		Staff: []goapi.ProductScheduleSessionUser{
 			{
 				TenantID:                 "tenant123",
 				ProductScheduleSessionID: "session123",
 				UserID:                   "user123",
-				User:                     &goapi.User{ID: "user123", Name: "John Doe"},
-				Role:                     "Instructor",
+				Role:                     goapi.StaffRoleInstructor,
 			},
 		},
 		Resources: []goapi.ProductScheduleSessionResource{
@@ -128,7 +126,7 @@ func handle() {
 				TenantID:                 "tenant123",
 				ProductScheduleSessionID: "session123",
 				ResourceID:               "resource123",
-				BeginTime:                "09:00",
+				BeginTime:                &goapi.TimeOfDay{Hour: 9},
 				DurationMinutes:          60,
 			},
 		},
@@ -0,0 +1,351 @@
+package goapitest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/classify-api/goapi"
+)
+
+// ErrUnmatchedRequest is returned by a Replayer for requests that are not
+// in its cassette
+var ErrUnmatchedRequest = errors.New("goapitest: request not found in cassette")
+
+// Redacted replaces secrets in recordings
+const Redacted = "REDACTED"
+
+// RedactedEmail replaces email addresses in recordings
+const RedactedEmail = "redacted@example.com"
+
+// RedactedJWT replaces JWTs in recordings. It is a well-formed unsigned JWT
+// without an exp claim, so clients replaying it never try to renew it.
+const RedactedJWT = "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJyZWRhY3RlZCI6dHJ1ZX0.REDACTED"
+
+var (
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// secretHeaders are headers whose values are always redacted
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization", "X-Api-Key"}
+
+// Interaction is a recorded request/response pair, one line of a cassette
+type Interaction struct {
+	Method         string      `json:"method"`
+	Path           string      `json:"path"`
+	Query          string      `json:"query,omitempty"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	Status         int         `json:"status"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records every interaction to a
+// cassette in JSON Lines format, with JWTs and emails redacted, along with
+// the values of the secret fields listed by goapi.IsSecretField such as
+// passwords and access tokens
+type Recorder struct {
+	transport http.RoundTripper
+
+	// Scrub, if set, is called on every interaction after the built-in
+	// redaction and before it is written
+	Scrub func(*Interaction)
+
+	mu  sync.Mutex // Mutex for thread-safe access to the cassette
+	out io.Writer
+}
+
+// NewRecorder returns a Recorder that sends requests through transport
+// (http.DefaultTransport if nil) and writes the interactions to out
+func NewRecorder(out io.Writer, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport, out: out}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Method:         req.Method,
+		Path:           scrubText(req.URL.Path),
+		Query:          scrubQuery(req.URL.RawQuery),
+		RequestHeader:  scrubHeader(req.Header),
+		RequestBody:    scrubBody(requestBody, req.Header.Get("Content-Type")),
+		Status:         resp.StatusCode,
+		ResponseHeader: scrubHeader(resp.Header),
+		ResponseBody:   scrubBody(responseBody, resp.Header.Get("Content-Type")),
+	}
+	if r.Scrub != nil {
+		r.Scrub(&interaction)
+	}
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding interaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.out.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("error writing cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette.
+// Each recorded interaction is used once, in order among those matching the
+// same method, path, query and body; any other request fails with
+// ErrUnmatchedRequest.
+type Replayer struct {
+	// IgnoreBodies matches requests on method, path and query only
+	IgnoreBodies bool
+
+	mu           sync.Mutex // Mutex for thread-safe access to the fields below
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer reads a cassette written by a Recorder
+func NewReplayer(in io.Reader) (*Replayer, error) {
+	var interactions []Interaction
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("error decoding cassette line %d: %w", line, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	return &Replayer{interactions: interactions, used: make([]bool, len(interactions))}, nil
+}
+
+// LoadCassette reads a cassette file written by a Recorder
+func LoadCassette(path string) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening cassette: %w", err)
+	}
+	defer file.Close()
+	return NewReplayer(file)
+}
+
+// RoundTrip implements http.RoundTripper
+func (p *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+	}
+	path := scrubText(req.URL.Path)
+	query := scrubQuery(req.URL.RawQuery)
+	body := scrubBody(requestBody, req.Header.Get("Content-Type"))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, interaction := range p.interactions {
+		if p.used[i] || interaction.Method != req.Method || interaction.Path != path ||
+			interaction.Query != query || (!p.IgnoreBodies && interaction.RequestBody != body) {
+			continue
+		}
+		p.used[i] = true
+		header := interaction.ResponseHeader.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        strconv.Itoa(interaction.Status) + " " + http.StatusText(interaction.Status),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.ResponseBody)),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	target := req.Method + " " + path
+	if query != "" {
+		target += "?" + query
+	}
+	if body != "" {
+		return nil, fmt.Errorf("%w: %s with body %s", ErrUnmatchedRequest, target, body)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnmatchedRequest, target)
+}
+
+// Unused returns the interactions that have not been replayed yet
+func (p *Replayer) Unused() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range p.interactions {
+		if !p.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func scrubText(s string) string {
+	s = jwtPattern.ReplaceAllString(s, RedactedJWT)
+	return emailPattern.ReplaceAllString(s, RedactedEmail)
+}
+
+// scrubQuery redacts a query string and puts it in canonical order
+func scrubQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return scrubText(rawQuery)
+	}
+	return scrubValues(query).Encode()
+}
+
+func scrubValues(values url.Values) url.Values {
+	scrubbed := make(url.Values, len(values))
+	for key, list := range values {
+		for _, value := range list {
+			if goapi.IsSecretField(key) {
+				value = redactSecret(value)
+			}
+			scrubbed.Add(key, scrubText(value))
+		}
+	}
+	return scrubbed
+}
+
+func scrubHeader(header http.Header) http.Header {
+	scrubbed := make(http.Header, len(header))
+	for key, values := range header {
+		for _, value := range values {
+			scrubbed.Add(key, scrubText(value))
+		}
+	}
+	// Redaction can change the length of the body
+	scrubbed.Del("Content-Length")
+	for _, key := range secretHeaders {
+		if values := scrubbed.Values(key); len(values) > 0 {
+			scrubbed.Set(key, Redacted)
+		}
+	}
+	return scrubbed
+}
+
+// scrubBody redacts a body. JSON and form bodies are re-encoded in canonical
+// form so that recorded and replayed requests compare equal.
+func scrubBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return scrubValues(values).Encode()
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if decoder.Decode(&value) == nil && !decoder.More() {
+		if scrubbed, err := json.Marshal(scrubJSON(value)); err == nil {
+			return string(scrubbed)
+		}
+	}
+	return scrubText(string(body))
+}
+
+func scrubJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if goapi.IsSecretField(key) {
+				v[key] = redactJSONSecret(field)
+			} else {
+				v[key] = scrubJSON(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scrubJSON(item)
+		}
+	case string:
+		return scrubText(v)
+	}
+	return value
+}
+
+// redactSecret replaces a secret, keeping JWTs well-formed so that replayed
+// login responses still parse
+func redactSecret(value string) string {
+	if jwtPattern.MatchString(value) {
+		return RedactedJWT
+	}
+	return Redacted
+}
+
+// redactJSONSecret replaces every string in the value of a secret field,
+// keeping its shape, e.g. the tenant keys of a tokens map
+func redactJSONSecret(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			v[key] = redactJSONSecret(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSONSecret(item)
+		}
+	case string:
+		return redactSecret(v)
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package goapitest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+// record runs session against a fake server through a Recorder and returns
+// the cassette
+func record(t *testing.T, session func(*goapi.Client)) []byte {
+	t.Helper()
+	srv := goapitest.NewServer()
+	defer srv.Close()
+	srv.AddAccount(goapitest.TestEmail, goapitest.TestPassword, "gym-north")
+
+	var cassette bytes.Buffer
+	recorder := goapitest.NewRecorder(&cassette, nil)
+	client := goapi.New(srv.URL,
+		goapi.WithHTTPClient(&http.Client{Transport: recorder}),
+		goapi.WithTenantName("gym-north"),
+	)
+	if err := client.Login(goapitest.TestEmail, goapitest.TestPassword); err != nil {
+		t.Fatal(err)
+	}
+	session(client)
+	return cassette.Bytes()
+}
+
+// replay returns a client answered from cassette
+func replay(t *testing.T, cassette []byte) (*goapitest.Replayer, *goapi.Client) {
+	t.Helper()
+	replayer, err := goapitest.NewReplayer(bytes.NewReader(cassette))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := goapi.New("http://classify.invalid",
+		goapi.WithHTTPClient(&http.Client{Transport: replayer}),
+		goapi.WithTenantName("gym-north"),
+		goapi.WithRetryPolicy(goapi.NoRetries()),
+	)
+	return replayer, client
+}
+
+func TestRecorderRedactsSecrets(t *testing.T) {
+	var token string
+	cassette := record(t, func(client *goapi.Client) {
+		token, _ = client.GetJWT()
+		if _, err := client.CreateUser(goapi.User{Name: "Ana", Email: "ana@example.com", Password: "hunter2"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	for _, secret := range []string{goapitest.TestPassword, "hunter2", goapitest.TestEmail, "ana@example.com", token} {
+		if bytes.Contains(cassette, []byte(secret)) {
+			t.Errorf("cassette contains %q:\n%s", secret, cassette)
+		}
+	}
+	for _, want := range []string{`\"password\":\"REDACTED\"`, goapitest.RedactedJWT, goapitest.RedactedEmail, `"Authorization":["REDACTED"]`} {
+		if !bytes.Contains(cassette, []byte(want)) {
+			t.Errorf("cassette lacks %s:\n%s", want, cassette)
+		}
+	}
+}
+
+func TestRecorderRedactsOpaqueTokens(t *testing.T) {
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"opaque-access","refresh_token":"opaque-refresh","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokens.Close()
+
+	var cassette bytes.Buffer
+	source := goapi.ClientCredentials(goapi.ClientCredentialsConfig{
+		TokenURL:       tokens.URL,
+		ClientID:       "kiosk",
+		ClientSecret:   "client-secret-value",
+		EndpointParams: map[string][]string{"client_secret": {"client-secret-value"}},
+		HTTPClient:     &http.Client{Transport: goapitest.NewRecorder(&cassette, nil)},
+	})
+	if _, err := source.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"opaque-access", "opaque-refresh", "client-secret-value"} {
+		if strings.Contains(cassette.String(), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, cassette.String())
+		}
+	}
+	if !strings.Contains(cassette.String(), `\"expires_in\":3600`) {
+		t.Errorf("cassette lost the token expiry:\n%s", cassette.String())
+	}
+}
+
+func TestReplayerReplaysRecording(t *testing.T) {
+	var recorded []goapi.Product
+	cassette := record(t, func(client *goapi.Client) {
+		for _, name := range []string{"Yoga", "Spin"} {
+			if _, err := client.CreateProduct(goapi.Product{Name: name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var err error
+		if recorded, err = client.GetProducts(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	replayer, client := replay(t, cassette)
+	if err := client.Login(goapitest.TestEmail, goapitest.TestPassword); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Yoga", "Spin"} {
+		if _, err := client.CreateProduct(goapi.Product{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	products, err := client.GetProducts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != len(recorded) || products[0] != recorded[0] || products[1] != recorded[1] {
+		t.Errorf("replayed %+v, recorded %+v", products, recorded)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("unused interactions: %+v", unused)
+	}
+
+	// Every interaction is used once
+	if _, err := client.GetProducts(); !errors.Is(err, goapitest.ErrUnmatchedRequest) {
+		t.Errorf("second replay of GET /products: err = %v, want ErrUnmatchedRequest", err)
+	}
+}
+
+func TestReplayerMatchesBodies(t *testing.T) {
+	cassette := record(t, func(client *goapi.Client) {
+		if _, err := client.CreateProduct(goapi.Product{Name: "Yoga"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	_, client := replay(t, cassette)
+	if _, err := client.CreateProduct(goapi.Product{Name: "Pilates"}); !errors.Is(err, goapitest.ErrUnmatchedRequest) {
+		t.Errorf("different body: err = %v, want ErrUnmatchedRequest", err)
+	}
+	if err := client.Login("someone@example.com", "other"); err != nil {
+		t.Errorf("login with other credentials, redacted alike: %v", err)
+	}
+
+	replayer, client := replay(t, cassette)
+	replayer.IgnoreBodies = true
+	if _, err := client.CreateProduct(goapi.Product{Name: "Pilates"}); err != nil {
+		t.Errorf("IgnoreBodies: %v", err)
+	}
+}
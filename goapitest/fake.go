@@ -0,0 +1,125 @@
+package goapitest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Call is a call received by a fake service. Args holds the arguments after
+// the context.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Handler computes the response of a fake to a call
+type Handler func(ctx context.Context, args []interface{}) (interface{}, error)
+
+// Fake records the calls made to a fake service and serves scripted
+// responses. The zero value is ready to use.
+//
+// Responses queued with Returns are served in order; once they run out the
+// fake uses the Handler set with Handle, or else returns the zero value and
+// a nil error. A *T result may be scripted as a T.
+type Fake struct {
+	mu        sync.Mutex // Mutex for thread-safe access to the fields below
+	calls     []Call
+	responses map[string][]response
+	handlers  map[string]Handler
+}
+
+type response struct {
+	result interface{}
+	err    error
+}
+
+// Returns queues a response for the next call to method
+func (f *Fake) Returns(method string, result interface{}, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.responses == nil {
+		f.responses = make(map[string][]response)
+	}
+	f.responses[method] = append(f.responses[method], response{result: result, err: err})
+}
+
+// Handle sets the handler answering calls to method once its queued
+// responses run out
+func (f *Fake) Handle(method string, handler Handler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.handlers == nil {
+		f.handlers = make(map[string]Handler)
+	}
+	f.handlers[method] = handler
+}
+
+// Calls returns the calls received so far
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo returns the calls to method received so far
+func (f *Fake) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls, queued responses and handlers
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.responses = nil
+	f.handlers = nil
+}
+
+// respond records a call and returns its scripted response as a T
+func respond[T any](ctx context.Context, f *Fake, method string, args ...interface{}) (T, error) {
+	var zero T
+
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	var next response
+	queued := len(f.responses[method]) > 0
+	if queued {
+		next = f.responses[method][0]
+		f.responses[method] = f.responses[method][1:]
+	}
+	handler := f.handlers[method]
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if !queued && handler != nil {
+		next.result, next.err = handler(ctx, args)
+	}
+	if next.result == nil {
+		return zero, next.err
+	}
+	if result, ok := next.result.(T); ok {
+		return result, next.err
+	}
+
+	// Accept a T where a *T is returned
+	want := reflect.TypeOf(&zero).Elem()
+	value := reflect.ValueOf(next.result)
+	if want.Kind() == reflect.Pointer && value.Type() == want.Elem() {
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		return pointer.Interface().(T), next.err
+	}
+	panic(fmt.Sprintf("goapitest: %s returns %v, scripted response is %T", method, want, next.result))
+}
@@ -0,0 +1,136 @@
+package goapitest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+func TestFakeReturnsBeforeHandle(t *testing.T) {
+	var users goapitest.FakeUserService
+	errGone := errors.New("gone")
+	users.Returns("GetUser", &goapi.User{ID: "u1"}, nil)
+	users.Returns("GetUser", nil, errGone)
+	users.Handle("GetUser", func(ctx context.Context, args []interface{}) (interface{}, error) {
+		return &goapi.User{ID: "handled-" + args[0].(string)}, nil
+	})
+
+	if user, err := users.GetUser("a"); err != nil || user.ID != "u1" {
+		t.Errorf("first call = %+v, %v; want the first queued user", user, err)
+	}
+	if user, err := users.GetUser("b"); err != errGone || user != nil {
+		t.Errorf("second call = %+v, %v; want the queued error", user, err)
+	}
+	for _, id := range []string{"c", "d"} {
+		if user, err := users.GetUser(id); err != nil || user.ID != "handled-"+id {
+			t.Errorf("call after the queue = %+v, %v; want the handler's user", user, err)
+		}
+	}
+
+	// Methods without a script return the zero value
+	if list, err := users.GetUsers(); list != nil || err != nil {
+		t.Errorf("unscripted GetUsers = %v, %v; want nil, nil", list, err)
+	}
+	if err := users.DeleteUser("a"); err != nil {
+		t.Errorf("unscripted DeleteUser = %v", err)
+	}
+}
+
+func TestFakeConvertsValueToPointer(t *testing.T) {
+	var users goapitest.FakeUserService
+	users.Returns("CreateUser", goapi.User{ID: "u1", Name: "Coach"}, nil)
+	users.Handle("UpdateUser", func(ctx context.Context, args []interface{}) (interface{}, error) {
+		return args[1], nil
+	})
+
+	user, err := users.CreateUser(goapi.User{Name: "Coach"})
+	if err != nil || user == nil || user.ID != "u1" || user.Name != "Coach" {
+		t.Errorf("CreateUser = %+v, %v; want a pointer to the scripted user", user, err)
+	}
+	user, err = users.UpdateUser("u1", goapi.User{ID: "u1", Name: "Head coach"})
+	if err != nil || user == nil || user.Name != "Head coach" {
+		t.Errorf("UpdateUser = %+v, %v; want a pointer to the handler's user", user, err)
+	}
+}
+
+func TestFakeWrongTypePanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+	}{
+		{name: "unrelated type", result: "u1"},
+		{name: "pointer for a slice", result: &[]goapi.User{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users goapitest.FakeUserService
+			users.Returns("GetUser", tt.result, nil)
+			users.Returns("GetUsers", tt.result, nil)
+
+			for method, call := range map[string]func(){
+				"GetUser":  func() { users.GetUser("u1") },
+				"GetUsers": func() { users.GetUsers() },
+			} {
+				func() {
+					defer func() {
+						msg := fmt.Sprint(recover())
+						if !strings.Contains(msg, method) || !strings.Contains(msg, fmt.Sprintf("%T", tt.result)) {
+							t.Errorf("%s panic = %q, want one naming the method and the scripted type", method, msg)
+						}
+					}()
+					call()
+				}()
+			}
+		})
+	}
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	var users goapitest.FakeUserService
+	users.Returns("GetUser", goapi.User{ID: "u1"}, nil)
+	ctx := context.Background()
+
+	users.GetUsersContext(ctx)
+	users.GetUser("u1")
+	users.UpdateUser("u2", goapi.User{Name: "Coach"})
+	users.GetUser("u3")
+
+	want := []goapitest.Call{
+		{Method: "GetUsers"},
+		{Method: "GetUser", Args: []interface{}{"u1"}},
+		{Method: "UpdateUser", Args: []interface{}{"u2", goapi.User{Name: "Coach"}}},
+		{Method: "GetUser", Args: []interface{}{"u3"}},
+	}
+	if got := users.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %+v, want %+v", got, want)
+	}
+	if got := users.CallsTo("GetUser"); !reflect.DeepEqual(got, []goapitest.Call{want[1], want[3]}) {
+		t.Errorf("CallsTo(GetUser) = %+v", got)
+	}
+
+	// A cancelled call is recorded and fails with the context error
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	users.Returns("DeleteUser", nil, errors.New("scripted"))
+	if err := users.DeleteUserContext(cancelled, "u1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled DeleteUser = %v, want context.Canceled", err)
+	}
+	if got := users.CallsTo("DeleteUser"); len(got) != 1 {
+		t.Errorf("DeleteUser calls = %+v, want 1", got)
+	}
+
+	users.Returns("GetUser", goapi.User{ID: "u4"}, nil)
+	users.Reset()
+	if calls := users.Calls(); len(calls) != 0 {
+		t.Errorf("calls after Reset = %+v", calls)
+	}
+	if user, err := users.GetUser("u1"); user != nil || err != nil {
+		t.Errorf("GetUser after Reset = %+v, %v; want nil, nil", user, err)
+	}
+}
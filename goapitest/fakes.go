@@ -0,0 +1,597 @@
+package goapitest
+
+import (
+	"context"
+
+	"github.com/classify-api/goapi"
+)
+
+// FakeUserService is a fake goapi.UserService
+type FakeUserService struct {
+	Fake
+}
+
+var _ goapi.UserService = (*FakeUserService)(nil)
+
+// GetUsers records the call and returns the next scripted response
+func (f *FakeUserService) GetUsers() ([]goapi.User, error) {
+	return f.GetUsersContext(context.Background())
+}
+
+// GetUsersContext records the call and returns the next scripted response
+func (f *FakeUserService) GetUsersContext(ctx context.Context) ([]goapi.User, error) {
+	return respond[[]goapi.User](ctx, &f.Fake, "GetUsers")
+}
+
+// GetUser records the call and returns the next scripted response
+func (f *FakeUserService) GetUser(userID string) (*goapi.User, error) {
+	return f.GetUserContext(context.Background(), userID)
+}
+
+// GetUserContext records the call and returns the next scripted response
+func (f *FakeUserService) GetUserContext(ctx context.Context, userID string) (*goapi.User, error) {
+	return respond[*goapi.User](ctx, &f.Fake, "GetUser", userID)
+}
+
+// CreateUser records the call and returns the next scripted response
+func (f *FakeUserService) CreateUser(user goapi.User) (*goapi.User, error) {
+	return f.CreateUserContext(context.Background(), user)
+}
+
+// CreateUserContext records the call and returns the next scripted response
+func (f *FakeUserService) CreateUserContext(ctx context.Context, user goapi.User) (*goapi.User, error) {
+	return respond[*goapi.User](ctx, &f.Fake, "CreateUser", user)
+}
+
+// UpdateUser records the call and returns the next scripted response
+func (f *FakeUserService) UpdateUser(userID string, user goapi.User) (*goapi.User, error) {
+	return f.UpdateUserContext(context.Background(), userID, user)
+}
+
+// UpdateUserContext records the call and returns the next scripted response
+func (f *FakeUserService) UpdateUserContext(ctx context.Context, userID string, user goapi.User) (*goapi.User, error) {
+	return respond[*goapi.User](ctx, &f.Fake, "UpdateUser", userID, user)
+}
+
+// DeleteUser records the call and returns the next scripted response
+func (f *FakeUserService) DeleteUser(userID string) error {
+	return f.DeleteUserContext(context.Background(), userID)
+}
+
+// DeleteUserContext records the call and returns the next scripted response
+func (f *FakeUserService) DeleteUserContext(ctx context.Context, userID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteUser", userID)
+	return err
+}
+
+// FakeTimeService is a fake goapi.TimeService
+type FakeTimeService struct {
+	Fake
+}
+
+var _ goapi.TimeService = (*FakeTimeService)(nil)
+
+// GetTimeSheets records the call and returns the next scripted response
+func (f *FakeTimeService) GetTimeSheets(userProfileID string) ([]goapi.ProfileTimeSheet, error) {
+	return f.GetTimeSheetsContext(context.Background(), userProfileID)
+}
+
+// GetTimeSheetsContext records the call and returns the next scripted response
+func (f *FakeTimeService) GetTimeSheetsContext(ctx context.Context, userProfileID string) ([]goapi.ProfileTimeSheet, error) {
+	return respond[[]goapi.ProfileTimeSheet](ctx, &f.Fake, "GetTimeSheets", userProfileID)
+}
+
+// CreateTimeSheet records the call and returns the next scripted response
+func (f *FakeTimeService) CreateTimeSheet(timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.CreateTimeSheetContext(context.Background(), timeSheet)
+}
+
+// CreateTimeSheetContext records the call and returns the next scripted response
+func (f *FakeTimeService) CreateTimeSheetContext(ctx context.Context, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "CreateTimeSheet", timeSheet)
+}
+
+// UpdateTimeSheet records the call and returns the next scripted response
+func (f *FakeTimeService) UpdateTimeSheet(timeSheetID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.UpdateTimeSheetContext(context.Background(), timeSheetID, timeSheet)
+}
+
+// UpdateTimeSheetContext records the call and returns the next scripted response
+func (f *FakeTimeService) UpdateTimeSheetContext(ctx context.Context, timeSheetID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "UpdateTimeSheet", timeSheetID, timeSheet)
+}
+
+// DeleteTimeSheet records the call and returns the next scripted response
+func (f *FakeTimeService) DeleteTimeSheet(timeSheetID string) error {
+	return f.DeleteTimeSheetContext(context.Background(), timeSheetID)
+}
+
+// DeleteTimeSheetContext records the call and returns the next scripted response
+func (f *FakeTimeService) DeleteTimeSheetContext(ctx context.Context, timeSheetID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteTimeSheet", timeSheetID)
+	return err
+}
+
+// ClockIn records the call and returns the next scripted response
+func (f *FakeTimeService) ClockIn(userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.ClockInContext(context.Background(), userProfileID, timeSheet)
+}
+
+// ClockInContext records the call and returns the next scripted response
+func (f *FakeTimeService) ClockInContext(ctx context.Context, userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "ClockIn", userProfileID, timeSheet)
+}
+
+// ClockOut records the call and returns the next scripted response
+func (f *FakeTimeService) ClockOut(userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.ClockOutContext(context.Background(), userProfileID, timeSheet)
+}
+
+// ClockOutContext records the call and returns the next scripted response
+func (f *FakeTimeService) ClockOutContext(ctx context.Context, userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "ClockOut", userProfileID, timeSheet)
+}
+
+// FakeReimbursementService is a fake goapi.ReimbursementService
+type FakeReimbursementService struct {
+	Fake
+}
+
+var _ goapi.ReimbursementService = (*FakeReimbursementService)(nil)
+
+// GetReimbursements records the call and returns the next scripted response
+func (f *FakeReimbursementService) GetReimbursements(userProfileID string) ([]goapi.ProfileReimbursement, error) {
+	return f.GetReimbursementsContext(context.Background(), userProfileID)
+}
+
+// GetReimbursementsContext records the call and returns the next scripted response
+func (f *FakeReimbursementService) GetReimbursementsContext(ctx context.Context, userProfileID string) ([]goapi.ProfileReimbursement, error) {
+	return respond[[]goapi.ProfileReimbursement](ctx, &f.Fake, "GetReimbursements", userProfileID)
+}
+
+// CreateReimbursement records the call and returns the next scripted response
+func (f *FakeReimbursementService) CreateReimbursement(reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return f.CreateReimbursementContext(context.Background(), reimbursement)
+}
+
+// CreateReimbursementContext records the call and returns the next scripted response
+func (f *FakeReimbursementService) CreateReimbursementContext(ctx context.Context, reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return respond[*goapi.ProfileReimbursement](ctx, &f.Fake, "CreateReimbursement", reimbursement)
+}
+
+// UpdateReimbursement records the call and returns the next scripted response
+func (f *FakeReimbursementService) UpdateReimbursement(reimbursementID string, reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return f.UpdateReimbursementContext(context.Background(), reimbursementID, reimbursement)
+}
+
+// UpdateReimbursementContext records the call and returns the next scripted response
+func (f *FakeReimbursementService) UpdateReimbursementContext(ctx context.Context, reimbursementID string, reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return respond[*goapi.ProfileReimbursement](ctx, &f.Fake, "UpdateReimbursement", reimbursementID, reimbursement)
+}
+
+// DeleteReimbursement records the call and returns the next scripted response
+func (f *FakeReimbursementService) DeleteReimbursement(reimbursementID string) error {
+	return f.DeleteReimbursementContext(context.Background(), reimbursementID)
+}
+
+// DeleteReimbursementContext records the call and returns the next scripted response
+func (f *FakeReimbursementService) DeleteReimbursementContext(ctx context.Context, reimbursementID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteReimbursement", reimbursementID)
+	return err
+}
+
+// FakeProductService is a fake goapi.ProductService
+type FakeProductService struct {
+	Fake
+}
+
+var _ goapi.ProductService = (*FakeProductService)(nil)
+
+// GetProducts records the call and returns the next scripted response
+func (f *FakeProductService) GetProducts() ([]goapi.Product, error) {
+	return f.GetProductsContext(context.Background())
+}
+
+// GetProductsContext records the call and returns the next scripted response
+func (f *FakeProductService) GetProductsContext(ctx context.Context) ([]goapi.Product, error) {
+	return respond[[]goapi.Product](ctx, &f.Fake, "GetProducts")
+}
+
+// FilterProducts records the call and returns the next scripted response
+func (f *FakeProductService) FilterProducts(filters map[string]string) ([]goapi.Product, error) {
+	return f.FilterProductsContext(context.Background(), filters)
+}
+
+// FilterProductsContext records the call and returns the next scripted response
+func (f *FakeProductService) FilterProductsContext(ctx context.Context, filters map[string]string) ([]goapi.Product, error) {
+	return respond[[]goapi.Product](ctx, &f.Fake, "FilterProducts", filters)
+}
+
+// GetProduct records the call and returns the next scripted response
+func (f *FakeProductService) GetProduct(productID string) (*goapi.Product, error) {
+	return f.GetProductContext(context.Background(), productID)
+}
+
+// GetProductContext records the call and returns the next scripted response
+func (f *FakeProductService) GetProductContext(ctx context.Context, productID string) (*goapi.Product, error) {
+	return respond[*goapi.Product](ctx, &f.Fake, "GetProduct", productID)
+}
+
+// CreateProduct records the call and returns the next scripted response
+func (f *FakeProductService) CreateProduct(product goapi.Product) (*goapi.Product, error) {
+	return f.CreateProductContext(context.Background(), product)
+}
+
+// CreateProductContext records the call and returns the next scripted response
+func (f *FakeProductService) CreateProductContext(ctx context.Context, product goapi.Product) (*goapi.Product, error) {
+	return respond[*goapi.Product](ctx, &f.Fake, "CreateProduct", product)
+}
+
+// UpdateProduct records the call and returns the next scripted response
+func (f *FakeProductService) UpdateProduct(productID string, product goapi.Product) (*goapi.Product, error) {
+	return f.UpdateProductContext(context.Background(), productID, product)
+}
+
+// UpdateProductContext records the call and returns the next scripted response
+func (f *FakeProductService) UpdateProductContext(ctx context.Context, productID string, product goapi.Product) (*goapi.Product, error) {
+	return respond[*goapi.Product](ctx, &f.Fake, "UpdateProduct", productID, product)
+}
+
+// DeleteProduct records the call and returns the next scripted response
+func (f *FakeProductService) DeleteProduct(productID string) error {
+	return f.DeleteProductContext(context.Background(), productID)
+}
+
+// DeleteProductContext records the call and returns the next scripted response
+func (f *FakeProductService) DeleteProductContext(ctx context.Context, productID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteProduct", productID)
+	return err
+}
+
+// FakeScheduleService is a fake goapi.ScheduleService
+type FakeScheduleService struct {
+	Fake
+}
+
+var _ goapi.ScheduleService = (*FakeScheduleService)(nil)
+
+// CreateProductSchedule records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductSchedule(schedule goapi.ProductSchedule) (*goapi.ProductSchedule, error) {
+	return f.CreateProductScheduleContext(context.Background(), schedule)
+}
+
+// CreateProductScheduleContext records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleContext(ctx context.Context, schedule goapi.ProductSchedule) (*goapi.ProductSchedule, error) {
+	return respond[*goapi.ProductSchedule](ctx, &f.Fake, "CreateProductSchedule", schedule)
+}
+
+// GetProductSchedule records the call and returns the next scripted response
+func (f *FakeScheduleService) GetProductSchedule(scheduleID string) (*goapi.ProductSchedule, error) {
+	return f.GetProductScheduleContext(context.Background(), scheduleID)
+}
+
+// GetProductScheduleContext records the call and returns the next scripted response
+func (f *FakeScheduleService) GetProductScheduleContext(ctx context.Context, scheduleID string) (*goapi.ProductSchedule, error) {
+	return respond[*goapi.ProductSchedule](ctx, &f.Fake, "GetProductSchedule", scheduleID)
+}
+
+// CreateProductScheduleSession records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleSession(session goapi.ProductScheduleSession) (*goapi.ProductScheduleSession, error) {
+	return f.CreateProductScheduleSessionContext(context.Background(), session)
+}
+
+// CreateProductScheduleSessionContext records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleSessionContext(ctx context.Context, session goapi.ProductScheduleSession) (*goapi.ProductScheduleSession, error) {
+	return respond[*goapi.ProductScheduleSession](ctx, &f.Fake, "CreateProductScheduleSession", session)
+}
+
+// GetProductScheduleSession records the call and returns the next scripted response
+func (f *FakeScheduleService) GetProductScheduleSession(sessionID string) (*goapi.ProductScheduleSession, error) {
+	return f.GetProductScheduleSessionContext(context.Background(), sessionID)
+}
+
+// GetProductScheduleSessionContext records the call and returns the next scripted response
+func (f *FakeScheduleService) GetProductScheduleSessionContext(ctx context.Context, sessionID string) (*goapi.ProductScheduleSession, error) {
+	return respond[*goapi.ProductScheduleSession](ctx, &f.Fake, "GetProductScheduleSession", sessionID)
+}
+
+// CreateProductScheduleSessionUser records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleSessionUser(sessionUser goapi.ProductScheduleSessionUser) (*goapi.ProductScheduleSessionUser, error) {
+	return f.CreateProductScheduleSessionUserContext(context.Background(), sessionUser)
+}
+
+// CreateProductScheduleSessionUserContext records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleSessionUserContext(ctx context.Context, sessionUser goapi.ProductScheduleSessionUser) (*goapi.ProductScheduleSessionUser, error) {
+	return respond[*goapi.ProductScheduleSessionUser](ctx, &f.Fake, "CreateProductScheduleSessionUser", sessionUser)
+}
+
+// CreateProductScheduleSessionResource records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleSessionResource(sessionResource goapi.ProductScheduleSessionResource) (*goapi.ProductScheduleSessionResource, error) {
+	return f.CreateProductScheduleSessionResourceContext(context.Background(), sessionResource)
+}
+
+// CreateProductScheduleSessionResourceContext records the call and returns the next scripted response
+func (f *FakeScheduleService) CreateProductScheduleSessionResourceContext(ctx context.Context, sessionResource goapi.ProductScheduleSessionResource) (*goapi.ProductScheduleSessionResource, error) {
+	return respond[*goapi.ProductScheduleSessionResource](ctx, &f.Fake, "CreateProductScheduleSessionResource", sessionResource)
+}
+
+// FakeAPI is a fake goapi.API
+type FakeAPI struct {
+	Fake
+}
+
+var _ goapi.API = (*FakeAPI)(nil)
+
+// GetUsers records the call and returns the next scripted response
+func (f *FakeAPI) GetUsers() ([]goapi.User, error) {
+	return f.GetUsersContext(context.Background())
+}
+
+// GetUsersContext records the call and returns the next scripted response
+func (f *FakeAPI) GetUsersContext(ctx context.Context) ([]goapi.User, error) {
+	return respond[[]goapi.User](ctx, &f.Fake, "GetUsers")
+}
+
+// GetUser records the call and returns the next scripted response
+func (f *FakeAPI) GetUser(userID string) (*goapi.User, error) {
+	return f.GetUserContext(context.Background(), userID)
+}
+
+// GetUserContext records the call and returns the next scripted response
+func (f *FakeAPI) GetUserContext(ctx context.Context, userID string) (*goapi.User, error) {
+	return respond[*goapi.User](ctx, &f.Fake, "GetUser", userID)
+}
+
+// CreateUser records the call and returns the next scripted response
+func (f *FakeAPI) CreateUser(user goapi.User) (*goapi.User, error) {
+	return f.CreateUserContext(context.Background(), user)
+}
+
+// CreateUserContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateUserContext(ctx context.Context, user goapi.User) (*goapi.User, error) {
+	return respond[*goapi.User](ctx, &f.Fake, "CreateUser", user)
+}
+
+// UpdateUser records the call and returns the next scripted response
+func (f *FakeAPI) UpdateUser(userID string, user goapi.User) (*goapi.User, error) {
+	return f.UpdateUserContext(context.Background(), userID, user)
+}
+
+// UpdateUserContext records the call and returns the next scripted response
+func (f *FakeAPI) UpdateUserContext(ctx context.Context, userID string, user goapi.User) (*goapi.User, error) {
+	return respond[*goapi.User](ctx, &f.Fake, "UpdateUser", userID, user)
+}
+
+// DeleteUser records the call and returns the next scripted response
+func (f *FakeAPI) DeleteUser(userID string) error {
+	return f.DeleteUserContext(context.Background(), userID)
+}
+
+// DeleteUserContext records the call and returns the next scripted response
+func (f *FakeAPI) DeleteUserContext(ctx context.Context, userID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteUser", userID)
+	return err
+}
+
+// GetTimeSheets records the call and returns the next scripted response
+func (f *FakeAPI) GetTimeSheets(userProfileID string) ([]goapi.ProfileTimeSheet, error) {
+	return f.GetTimeSheetsContext(context.Background(), userProfileID)
+}
+
+// GetTimeSheetsContext records the call and returns the next scripted response
+func (f *FakeAPI) GetTimeSheetsContext(ctx context.Context, userProfileID string) ([]goapi.ProfileTimeSheet, error) {
+	return respond[[]goapi.ProfileTimeSheet](ctx, &f.Fake, "GetTimeSheets", userProfileID)
+}
+
+// CreateTimeSheet records the call and returns the next scripted response
+func (f *FakeAPI) CreateTimeSheet(timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.CreateTimeSheetContext(context.Background(), timeSheet)
+}
+
+// CreateTimeSheetContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateTimeSheetContext(ctx context.Context, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "CreateTimeSheet", timeSheet)
+}
+
+// UpdateTimeSheet records the call and returns the next scripted response
+func (f *FakeAPI) UpdateTimeSheet(timeSheetID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.UpdateTimeSheetContext(context.Background(), timeSheetID, timeSheet)
+}
+
+// UpdateTimeSheetContext records the call and returns the next scripted response
+func (f *FakeAPI) UpdateTimeSheetContext(ctx context.Context, timeSheetID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "UpdateTimeSheet", timeSheetID, timeSheet)
+}
+
+// DeleteTimeSheet records the call and returns the next scripted response
+func (f *FakeAPI) DeleteTimeSheet(timeSheetID string) error {
+	return f.DeleteTimeSheetContext(context.Background(), timeSheetID)
+}
+
+// DeleteTimeSheetContext records the call and returns the next scripted response
+func (f *FakeAPI) DeleteTimeSheetContext(ctx context.Context, timeSheetID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteTimeSheet", timeSheetID)
+	return err
+}
+
+// ClockIn records the call and returns the next scripted response
+func (f *FakeAPI) ClockIn(userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.ClockInContext(context.Background(), userProfileID, timeSheet)
+}
+
+// ClockInContext records the call and returns the next scripted response
+func (f *FakeAPI) ClockInContext(ctx context.Context, userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "ClockIn", userProfileID, timeSheet)
+}
+
+// ClockOut records the call and returns the next scripted response
+func (f *FakeAPI) ClockOut(userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return f.ClockOutContext(context.Background(), userProfileID, timeSheet)
+}
+
+// ClockOutContext records the call and returns the next scripted response
+func (f *FakeAPI) ClockOutContext(ctx context.Context, userProfileID string, timeSheet goapi.ProfileTimeSheet) (*goapi.ProfileTimeSheet, error) {
+	return respond[*goapi.ProfileTimeSheet](ctx, &f.Fake, "ClockOut", userProfileID, timeSheet)
+}
+
+// GetReimbursements records the call and returns the next scripted response
+func (f *FakeAPI) GetReimbursements(userProfileID string) ([]goapi.ProfileReimbursement, error) {
+	return f.GetReimbursementsContext(context.Background(), userProfileID)
+}
+
+// GetReimbursementsContext records the call and returns the next scripted response
+func (f *FakeAPI) GetReimbursementsContext(ctx context.Context, userProfileID string) ([]goapi.ProfileReimbursement, error) {
+	return respond[[]goapi.ProfileReimbursement](ctx, &f.Fake, "GetReimbursements", userProfileID)
+}
+
+// CreateReimbursement records the call and returns the next scripted response
+func (f *FakeAPI) CreateReimbursement(reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return f.CreateReimbursementContext(context.Background(), reimbursement)
+}
+
+// CreateReimbursementContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateReimbursementContext(ctx context.Context, reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return respond[*goapi.ProfileReimbursement](ctx, &f.Fake, "CreateReimbursement", reimbursement)
+}
+
+// UpdateReimbursement records the call and returns the next scripted response
+func (f *FakeAPI) UpdateReimbursement(reimbursementID string, reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return f.UpdateReimbursementContext(context.Background(), reimbursementID, reimbursement)
+}
+
+// UpdateReimbursementContext records the call and returns the next scripted response
+func (f *FakeAPI) UpdateReimbursementContext(ctx context.Context, reimbursementID string, reimbursement goapi.ProfileReimbursement) (*goapi.ProfileReimbursement, error) {
+	return respond[*goapi.ProfileReimbursement](ctx, &f.Fake, "UpdateReimbursement", reimbursementID, reimbursement)
+}
+
+// DeleteReimbursement records the call and returns the next scripted response
+func (f *FakeAPI) DeleteReimbursement(reimbursementID string) error {
+	return f.DeleteReimbursementContext(context.Background(), reimbursementID)
+}
+
+// DeleteReimbursementContext records the call and returns the next scripted response
+func (f *FakeAPI) DeleteReimbursementContext(ctx context.Context, reimbursementID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteReimbursement", reimbursementID)
+	return err
+}
+
+// GetProducts records the call and returns the next scripted response
+func (f *FakeAPI) GetProducts() ([]goapi.Product, error) {
+	return f.GetProductsContext(context.Background())
+}
+
+// GetProductsContext records the call and returns the next scripted response
+func (f *FakeAPI) GetProductsContext(ctx context.Context) ([]goapi.Product, error) {
+	return respond[[]goapi.Product](ctx, &f.Fake, "GetProducts")
+}
+
+// FilterProducts records the call and returns the next scripted response
+func (f *FakeAPI) FilterProducts(filters map[string]string) ([]goapi.Product, error) {
+	return f.FilterProductsContext(context.Background(), filters)
+}
+
+// FilterProductsContext records the call and returns the next scripted response
+func (f *FakeAPI) FilterProductsContext(ctx context.Context, filters map[string]string) ([]goapi.Product, error) {
+	return respond[[]goapi.Product](ctx, &f.Fake, "FilterProducts", filters)
+}
+
+// GetProduct records the call and returns the next scripted response
+func (f *FakeAPI) GetProduct(productID string) (*goapi.Product, error) {
+	return f.GetProductContext(context.Background(), productID)
+}
+
+// GetProductContext records the call and returns the next scripted response
+func (f *FakeAPI) GetProductContext(ctx context.Context, productID string) (*goapi.Product, error) {
+	return respond[*goapi.Product](ctx, &f.Fake, "GetProduct", productID)
+}
+
+// CreateProduct records the call and returns the next scripted response
+func (f *FakeAPI) CreateProduct(product goapi.Product) (*goapi.Product, error) {
+	return f.CreateProductContext(context.Background(), product)
+}
+
+// CreateProductContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductContext(ctx context.Context, product goapi.Product) (*goapi.Product, error) {
+	return respond[*goapi.Product](ctx, &f.Fake, "CreateProduct", product)
+}
+
+// UpdateProduct records the call and returns the next scripted response
+func (f *FakeAPI) UpdateProduct(productID string, product goapi.Product) (*goapi.Product, error) {
+	return f.UpdateProductContext(context.Background(), productID, product)
+}
+
+// UpdateProductContext records the call and returns the next scripted response
+func (f *FakeAPI) UpdateProductContext(ctx context.Context, productID string, product goapi.Product) (*goapi.Product, error) {
+	return respond[*goapi.Product](ctx, &f.Fake, "UpdateProduct", productID, product)
+}
+
+// DeleteProduct records the call and returns the next scripted response
+func (f *FakeAPI) DeleteProduct(productID string) error {
+	return f.DeleteProductContext(context.Background(), productID)
+}
+
+// DeleteProductContext records the call and returns the next scripted response
+func (f *FakeAPI) DeleteProductContext(ctx context.Context, productID string) error {
+	_, err := respond[struct{}](ctx, &f.Fake, "DeleteProduct", productID)
+	return err
+}
+
+// CreateProductSchedule records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductSchedule(schedule goapi.ProductSchedule) (*goapi.ProductSchedule, error) {
+	return f.CreateProductScheduleContext(context.Background(), schedule)
+}
+
+// CreateProductScheduleContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleContext(ctx context.Context, schedule goapi.ProductSchedule) (*goapi.ProductSchedule, error) {
+	return respond[*goapi.ProductSchedule](ctx, &f.Fake, "CreateProductSchedule", schedule)
+}
+
+// GetProductSchedule records the call and returns the next scripted response
+func (f *FakeAPI) GetProductSchedule(scheduleID string) (*goapi.ProductSchedule, error) {
+	return f.GetProductScheduleContext(context.Background(), scheduleID)
+}
+
+// GetProductScheduleContext records the call and returns the next scripted response
+func (f *FakeAPI) GetProductScheduleContext(ctx context.Context, scheduleID string) (*goapi.ProductSchedule, error) {
+	return respond[*goapi.ProductSchedule](ctx, &f.Fake, "GetProductSchedule", scheduleID)
+}
+
+// CreateProductScheduleSession records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleSession(session goapi.ProductScheduleSession) (*goapi.ProductScheduleSession, error) {
+	return f.CreateProductScheduleSessionContext(context.Background(), session)
+}
+
+// CreateProductScheduleSessionContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleSessionContext(ctx context.Context, session goapi.ProductScheduleSession) (*goapi.ProductScheduleSession, error) {
+	return respond[*goapi.ProductScheduleSession](ctx, &f.Fake, "CreateProductScheduleSession", session)
+}
+
+// GetProductScheduleSession records the call and returns the next scripted response
+func (f *FakeAPI) GetProductScheduleSession(sessionID string) (*goapi.ProductScheduleSession, error) {
+	return f.GetProductScheduleSessionContext(context.Background(), sessionID)
+}
+
+// GetProductScheduleSessionContext records the call and returns the next scripted response
+func (f *FakeAPI) GetProductScheduleSessionContext(ctx context.Context, sessionID string) (*goapi.ProductScheduleSession, error) {
+	return respond[*goapi.ProductScheduleSession](ctx, &f.Fake, "GetProductScheduleSession", sessionID)
+}
+
+// CreateProductScheduleSessionUser records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleSessionUser(sessionUser goapi.ProductScheduleSessionUser) (*goapi.ProductScheduleSessionUser, error) {
+	return f.CreateProductScheduleSessionUserContext(context.Background(), sessionUser)
+}
+
+// CreateProductScheduleSessionUserContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleSessionUserContext(ctx context.Context, sessionUser goapi.ProductScheduleSessionUser) (*goapi.ProductScheduleSessionUser, error) {
+	return respond[*goapi.ProductScheduleSessionUser](ctx, &f.Fake, "CreateProductScheduleSessionUser", sessionUser)
+}
+
+// CreateProductScheduleSessionResource records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleSessionResource(sessionResource goapi.ProductScheduleSessionResource) (*goapi.ProductScheduleSessionResource, error) {
+	return f.CreateProductScheduleSessionResourceContext(context.Background(), sessionResource)
+}
+
+// CreateProductScheduleSessionResourceContext records the call and returns the next scripted response
+func (f *FakeAPI) CreateProductScheduleSessionResourceContext(ctx context.Context, sessionResource goapi.ProductScheduleSessionResource) (*goapi.ProductScheduleSessionResource, error) {
+	return respond[*goapi.ProductScheduleSessionResource](ctx, &f.Fake, "CreateProductScheduleSessionResource", sessionResource)
+}
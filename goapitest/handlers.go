@@ -0,0 +1,630 @@
+package goapitest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/classify-api/goapi"
+)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "error reading body")
+		return
+	}
+
+	s.mu.Lock()
+	index := len(s.requests)
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	latency := s.latency
+	var fault *Fault
+	for _, f := range s.faults {
+		if matchesFault(f, r) {
+			f.hits++
+			fault = f
+			break
+		}
+	}
+	var injected Fault
+	if fault != nil {
+		injected = *fault
+		latency += fault.Latency
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	if injected.Status == 0 || injected.Processed {
+		s.route(rec, r, body)
+	}
+	if injected.Status != 0 {
+		rec = httptest.NewRecorder()
+		for key, values := range injected.Header {
+			rec.Header()[key] = values
+		}
+		if injected.Body == "" {
+			writeError(rec, injected.Status, "injected", http.StatusText(injected.Status))
+		} else {
+			rec.WriteHeader(injected.Status)
+			io.WriteString(rec, injected.Body)
+		}
+	}
+
+	status := rec.Code
+	if r.Method == http.MethodGet && status == http.StatusOK {
+		sum := sha256.Sum256(rec.Body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		rec.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			status = http.StatusNotModified
+		}
+	}
+	for key, values := range rec.Header() {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(status)
+	if status != http.StatusNotModified {
+		w.Write(rec.Body.Bytes())
+	}
+
+	s.mu.Lock()
+	s.requests[index].Status = status
+	s.mu.Unlock()
+}
+
+// route handles an authenticated request. A mutating request repeating the
+// Idempotency-Key of an earlier one gets the earlier response instead.
+func (s *Server) route(w *httptest.ResponseRecorder, r *http.Request, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/login":
+		s.handleLogin(w, r, body)
+		return
+	case "/refresh":
+		s.handleRefresh(w, r)
+		return
+	}
+
+	tenant, ok := s.authenticate(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid token")
+		return
+	}
+
+	key := r.Header.Get(goapi.IdempotencyKeyHeader)
+	if r.Method == http.MethodGet || key == "" {
+		s.dispatch(w, r, tenant, body)
+		return
+	}
+	request := r.Method + " " + r.URL.RequestURI() + " " + string(body)
+	if previous, ok := s.replies[tenant+" "+key]; ok {
+		if previous.request != request {
+			writeError(w, http.StatusUnprocessableEntity, "idempotency_key_reused",
+				"idempotency key "+key+" was used for a different request")
+			return
+		}
+		for name, values := range previous.header {
+			w.Header()[name] = values
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(previous.status)
+		w.Write(previous.body)
+		return
+	}
+	s.dispatch(w, r, tenant, body)
+	if w.Code < http.StatusInternalServerError {
+		s.replies[tenant+" "+key] = reply{
+			request: request,
+			status:  w.Code,
+			header:  w.Header().Clone(),
+			body:    append([]byte(nil), w.Body.Bytes()...),
+		}
+	}
+}
+
+func (s *Server) dispatch(w http.ResponseWriter, r *http.Request, tenant string, body []byte) {
+	data := s.tenant(tenant)
+	resource, id, _ := strings.Cut(strings.Trim(r.URL.Path, "/"), "/")
+	switch resource {
+	case "users":
+		s.handleUsers(w, r, id, body)
+	case "time_sheets":
+		s.handleTimeSheets(w, r, tenant, data, id, body)
+	case "reimbursements":
+		s.handleReimbursements(w, r, tenant, data, id, body)
+	case "clock_in":
+		s.handleClockIn(w, r, tenant, data, body)
+	case "clock_out":
+		s.handleClockOut(w, r, data, body)
+	case "products":
+		s.handleProducts(w, r, data, id, body)
+	case "product_schedules":
+		s.handleSchedules(w, r, tenant, data, id, body)
+	case "product_schedule_sessions":
+		s.handleSessions(w, r, tenant, data, id, body)
+	case "product_schedule_session_users":
+		s.handleSessionUsers(w, r, tenant, data, body)
+	case "product_schedule_session_resources":
+		s.handleSessionResources(w, r, tenant, data, body)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "no route for "+r.URL.Path)
+	}
+}
+
+// authenticate returns the tenant of the bearer token of r
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	info, ok := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok || !s.Now().Before(info.expiry) {
+		return "", false
+	}
+	return info.tenant, true
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request, body []byte) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	var login goapi.LoginRequest
+	if json.Unmarshal(body, &login) != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid login request")
+		return
+	}
+	acct, ok := s.accounts[login.User.Email]
+	if !ok || acct.password != login.User.Password {
+		writeError(w, http.StatusUnauthorized, "invalid_credentials", "invalid email or password")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tokens": s.issueTokens(login.User.Email, acct)})
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	info, ok := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid token")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tokens": s.issueTokens(info.email, s.accounts[info.email])})
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		paginate(w, r, s.users.list(nil))
+	case id == "" && r.Method == http.MethodPost:
+		var user goapi.User
+		if !decode(w, body, &user) {
+			return
+		}
+		if user.Email == "" {
+			writeError(w, http.StatusUnprocessableEntity, "validation_failed", "user is invalid",
+				goapi.FieldError{Field: "email", Code: "required", Message: "email is required"})
+			return
+		}
+		now := s.timestamp()
+		user.ID = s.newID("usr")
+		user.Password = ""
+		user.DateFields = goapi.DateFields{CreatedAt: &now, UpdatedAt: &now}
+		s.users.put(user.ID, user)
+		writeJSON(w, http.StatusCreated, user)
+	case id != "":
+		existing, ok := s.users.get(id)
+		if !ok {
+			notFound(w, "user", id)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, existing)
+		case http.MethodPut:
+			var user goapi.User
+			if !decode(w, body, &user) {
+				return
+			}
+			now := s.timestamp()
+			user.ID = id
+			user.Password = ""
+			user.DateFields = goapi.DateFields{CreatedAt: existing.DateFields.CreatedAt, UpdatedAt: &now}
+			s.users.put(id, user)
+			writeJSON(w, http.StatusOK, user)
+		case http.MethodDelete:
+			s.users.delete(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleTimeSheets(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, id string, body []byte) {
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		profileID := r.URL.Query().Get("user_profile_id")
+		paginate(w, r, data.timeSheets.list(func(t goapi.ProfileTimeSheet) bool {
+			return profileID == "" || t.UserProfileID == profileID
+		}))
+	case id == "" && r.Method == http.MethodPost:
+		var timeSheet goapi.ProfileTimeSheet
+		if !decode(w, body, &timeSheet) {
+			return
+		}
+		timeSheet.ID = s.newID("ts")
+		timeSheet.TenantID = tenant
+		timeSheet.ManuallyEntered = true
+		data.timeSheets.put(timeSheet.ID, timeSheet)
+		writeJSON(w, http.StatusCreated, timeSheet)
+	case id != "":
+		existing, ok := data.timeSheets.get(id)
+		if !ok {
+			notFound(w, "time sheet", id)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var timeSheet goapi.ProfileTimeSheet
+			if !decode(w, body, &timeSheet) {
+				return
+			}
+			timeSheet.ID = id
+			timeSheet.TenantID = existing.TenantID
+			data.timeSheets.put(id, timeSheet)
+			writeJSON(w, http.StatusOK, timeSheet)
+		case http.MethodDelete:
+			data.timeSheets.delete(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleReimbursements(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, id string, body []byte) {
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		profileID := r.URL.Query().Get("user_profile_id")
+		paginate(w, r, data.reimbursements.list(func(rb goapi.ProfileReimbursement) bool {
+			return profileID == "" || rb.UserProfileID == profileID
+		}))
+	case id == "" && r.Method == http.MethodPost:
+		var reimbursement goapi.ProfileReimbursement
+		if !decode(w, body, &reimbursement) {
+			return
+		}
+		reimbursement.ID = s.newID("rb")
+		reimbursement.TenantID = tenant
+		if reimbursement.Status == "" {
+			reimbursement.Status = goapi.ReimbursementStatusPending
+		}
+		data.reimbursements.put(reimbursement.ID, reimbursement)
+		writeJSON(w, http.StatusCreated, reimbursement)
+	case id != "":
+		existing, ok := data.reimbursements.get(id)
+		if !ok {
+			notFound(w, "reimbursement", id)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var reimbursement goapi.ProfileReimbursement
+			if !decode(w, body, &reimbursement) {
+				return
+			}
+			reimbursement.ID = id
+			reimbursement.TenantID = existing.TenantID
+			data.reimbursements.put(id, reimbursement)
+			writeJSON(w, http.StatusOK, reimbursement)
+		case http.MethodDelete:
+			data.reimbursements.delete(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// handleClockIn opens a time sheet for the profile. A profile can only have
+// one open time sheet at a time.
+func (s *Server) handleClockIn(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, body []byte) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	profileID := r.URL.Query().Get("user_profile_id")
+	if _, open := openTimeSheet(data, profileID); open {
+		writeError(w, http.StatusConflict, "already_clocked_in", "profile "+profileID+" is already clocked in")
+		return
+	}
+	var timeSheet goapi.ProfileTimeSheet
+	if len(body) > 0 && !decode(w, body, &timeSheet) {
+		return
+	}
+	now := s.timestamp()
+	timeSheet.ID = s.newID("ts")
+	timeSheet.TenantID = tenant
+	timeSheet.UserProfileID = profileID
+	if timeSheet.TimeIn.IsZero() {
+		timeSheet.TimeIn = now
+	}
+	timeSheet.ActualTimeIn = &now
+	timeSheet.TimeOut = nil
+	timeSheet.ActualTimeOut = nil
+	if user, ok := s.profileUser(profileID); ok {
+		timeSheet.UserName = user.Name
+		timeSheet.UserEmail = user.Email
+	}
+	data.timeSheets.put(timeSheet.ID, timeSheet)
+	writeJSON(w, http.StatusCreated, timeSheet)
+}
+
+// handleClockOut closes the open time sheet of the profile
+func (s *Server) handleClockOut(w http.ResponseWriter, r *http.Request, data *tenantData, body []byte) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	profileID := r.URL.Query().Get("user_profile_id")
+	timeSheet, open := openTimeSheet(data, profileID)
+	if !open {
+		writeError(w, http.StatusConflict, "not_clocked_in", "profile "+profileID+" is not clocked in")
+		return
+	}
+	var update goapi.ProfileTimeSheet
+	if len(body) > 0 && !decode(w, body, &update) {
+		return
+	}
+	now := s.timestamp()
+	timeSheet.TimeOut = update.TimeOut
+	if timeSheet.TimeOut == nil {
+		timeSheet.TimeOut = &now
+	}
+	timeSheet.ActualTimeOut = &now
+	timeSheet.LatOut = update.LatOut
+	timeSheet.LngOut = update.LngOut
+	timeSheet.ImageOutURL = update.ImageOutURL
+	if update.Note != "" {
+		timeSheet.Note = update.Note
+	}
+	data.timeSheets.put(timeSheet.ID, timeSheet)
+	writeJSON(w, http.StatusOK, timeSheet)
+}
+
+func (s *Server) handleProducts(w http.ResponseWriter, r *http.Request, data *tenantData, id string, body []byte) {
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		paginate(w, r, data.products.list(nil))
+	case id == "filter" && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		writeJSON(w, http.StatusOK, data.products.list(func(p goapi.Product) bool {
+			return contains(p.Name, query.Get("name")) && contains(p.Description, query.Get("description"))
+		}))
+	case id == "" && r.Method == http.MethodPost:
+		var product goapi.Product
+		if !decode(w, body, &product) {
+			return
+		}
+		if product.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "validation_failed", "product is invalid",
+				goapi.FieldError{Field: "name", Code: "required", Message: "name is required"})
+			return
+		}
+		product.ID = s.newID("prod")
+		data.products.put(product.ID, product)
+		writeJSON(w, http.StatusCreated, product)
+	case id != "":
+		if _, ok := data.products.get(id); !ok {
+			notFound(w, "product", id)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			product, _ := data.products.get(id)
+			writeJSON(w, http.StatusOK, product)
+		case http.MethodPut:
+			var product goapi.Product
+			if !decode(w, body, &product) {
+				return
+			}
+			product.ID = id
+			data.products.put(id, product)
+			writeJSON(w, http.StatusOK, product)
+		case http.MethodDelete:
+			data.products.delete(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleSchedules(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, id string, body []byte) {
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var schedule goapi.ProductSchedule
+		if !decode(w, body, &schedule) {
+			return
+		}
+		if _, ok := data.products.get(schedule.ProductID); !ok {
+			notFound(w, "product", schedule.ProductID)
+			return
+		}
+		schedule.ID = s.newID("sched")
+		schedule.TenantID = tenant
+		schedule.Sessions = nil
+		data.schedules.put(schedule.ID, schedule)
+		writeJSON(w, http.StatusCreated, schedule)
+	case id != "" && r.Method == http.MethodGet:
+		schedule, ok := data.schedules.get(id)
+		if !ok {
+			notFound(w, "product schedule", id)
+			return
+		}
+		schedule.Sessions = data.sessions.list(func(session goapi.ProductScheduleSession) bool {
+			return session.ProductScheduleID == id
+		})
+		for i := range schedule.Sessions {
+			schedule.Sessions[i] = withAssignments(data, schedule.Sessions[i])
+		}
+		writeJSON(w, http.StatusOK, schedule)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, id string, body []byte) {
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var session goapi.ProductScheduleSession
+		if !decode(w, body, &session) {
+			return
+		}
+		if _, ok := data.schedules.get(session.ProductScheduleID); !ok {
+			notFound(w, "product schedule", session.ProductScheduleID)
+			return
+		}
+		session.ID = s.newID("sess")
+		session.TenantID = tenant
+		session.Staff = nil
+		session.Resources = nil
+		data.sessions.put(session.ID, session)
+		writeJSON(w, http.StatusCreated, session)
+	case id != "" && r.Method == http.MethodGet:
+		session, ok := data.sessions.get(id)
+		if !ok {
+			notFound(w, "product schedule session", id)
+			return
+		}
+		writeJSON(w, http.StatusOK, withAssignments(data, session))
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleSessionUsers(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, body []byte) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	var sessionUser goapi.ProductScheduleSessionUser
+	if !decode(w, body, &sessionUser) {
+		return
+	}
+	if _, ok := data.sessions.get(sessionUser.ProductScheduleSessionID); !ok {
+		notFound(w, "product schedule session", sessionUser.ProductScheduleSessionID)
+		return
+	}
+	sessionUser.TenantID = tenant
+	data.sessionUsers = append(data.sessionUsers, sessionUser)
+	writeJSON(w, http.StatusCreated, sessionUser)
+}
+
+func (s *Server) handleSessionResources(w http.ResponseWriter, r *http.Request, tenant string, data *tenantData, body []byte) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	var sessionResource goapi.ProductScheduleSessionResource
+	if !decode(w, body, &sessionResource) {
+		return
+	}
+	if _, ok := data.sessions.get(sessionResource.ProductScheduleSessionID); !ok {
+		notFound(w, "product schedule session", sessionResource.ProductScheduleSessionID)
+		return
+	}
+	sessionResource.TenantID = tenant
+	data.sessionResources = append(data.sessionResources, sessionResource)
+	writeJSON(w, http.StatusCreated, sessionResource)
+}
+
+// profileUser returns the user owning a profile
+func (s *Server) profileUser(profileID string) (goapi.User, bool) {
+	for _, user := range s.users.list(nil) {
+		for _, profile := range user.Profiles {
+			if profile.ID == profileID {
+				return user, true
+			}
+		}
+	}
+	return goapi.User{}, false
+}
+
+func openTimeSheet(data *tenantData, profileID string) (goapi.ProfileTimeSheet, bool) {
+	open := data.timeSheets.list(func(t goapi.ProfileTimeSheet) bool {
+		return t.UserProfileID == profileID && t.TimeOut == nil
+	})
+	if len(open) == 0 {
+		return goapi.ProfileTimeSheet{}, false
+	}
+	return open[len(open)-1], true
+}
+
+// withAssignments fills in the staff and resources assigned to session
+func withAssignments(data *tenantData, session goapi.ProductScheduleSession) goapi.ProductScheduleSession {
+	session.Staff, session.Resources = nil, nil
+	for _, staff := range data.sessionUsers {
+		if staff.ProductScheduleSessionID == session.ID {
+			session.Staff = append(session.Staff, staff)
+		}
+	}
+	for _, resource := range data.sessionResources {
+		if resource.ProductScheduleSessionID == session.ID {
+			session.Resources = append(session.Resources, resource)
+		}
+	}
+	return session
+}
+
+func decode(w http.ResponseWriter, body []byte, value interface{}) bool {
+	if err := json.Unmarshal(body, value); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func contains(value, substr string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substr))
+}
+
+func notFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, "not_found", kind+" "+id+" not found")
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed")
+}
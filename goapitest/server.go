@@ -0,0 +1,381 @@
+// Package goapitest provides an in-memory fake of the Classify API for
+// tests. It keeps state between calls, issues per-tenant login tokens,
+// answers repeated mutations carrying the same Idempotency-Key with the
+// original response, tags GET responses with an ETag honoring
+// If-None-Match, and can inject errors and latency, so code using goapi can
+// be tested offline:
+//
+//	srv := goapitest.NewServer()
+//	defer srv.Close()
+//	srv.AddAccount("coach@example.com", "secret", "gym-north", "gym-south")
+//
+//	client := srv.Client(goapi.WithTenantName("gym-north"))
+//	err := client.Login("coach@example.com", "secret")
+package goapitest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+)
+
+// Server is a stateful fake of the Classify API served over HTTP
+type Server struct {
+	URL string
+
+	// Now is the clock used for timestamps and token expiry
+	Now func() time.Time
+
+	// TokenTTL is the lifetime of the tokens issued by /login
+	TokenTTL time.Duration
+
+	srv *httptest.Server
+
+	mu       sync.Mutex // Mutex for thread-safe access to the fields below
+	nextID   int
+	accounts map[string]*account // By email
+	tokens   map[string]tokenInfo
+	replies  map[string]reply // By tenant and Idempotency-Key
+	users    *collection[goapi.User]
+	tenants  map[string]*tenantData
+	faults   []*Fault
+	latency  time.Duration
+	requests []Request
+}
+
+type account struct {
+	password string
+	userID   string
+	tenants  []string
+}
+
+// reply is the response to a mutating request, replayed when the request
+// is repeated with the same Idempotency-Key
+type reply struct {
+	request string // Method, path and body of the original request
+	status  int
+	header  http.Header
+	body    []byte
+}
+
+type tokenInfo struct {
+	email  string
+	tenant string
+	expiry time.Time
+}
+
+type tenantData struct {
+	timeSheets       *collection[goapi.ProfileTimeSheet]
+	reimbursements   *collection[goapi.ProfileReimbursement]
+	products         *collection[goapi.Product]
+	schedules        *collection[goapi.ProductSchedule]
+	sessions         *collection[goapi.ProductScheduleSession]
+	sessionUsers     []goapi.ProductScheduleSessionUser
+	sessionResources []goapi.ProductScheduleSessionResource
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	Status int // Status of the response, 0 while it is being handled
+}
+
+// Fault makes matching requests fail or slow down
+type Fault struct {
+	Method  string        // Method to match, empty for any
+	Path    string        // Path prefix to match, empty for any
+	Status  int           // Status to answer with, 0 to only add Latency
+	Body    string        // Response body, defaults to a JSON error
+	Header  http.Header   // Extra response headers, e.g. Retry-After
+	Latency time.Duration // Delay before answering
+	Times   int           // Number of requests affected, 0 for all
+
+	// Processed makes the server handle the request before answering with
+	// Status, as if the real response had been lost on the way back
+	Processed bool
+
+	hits int
+}
+
+// NewServer starts a fake Classify API. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Now:      time.Now,
+		TokenTTL: time.Hour,
+		accounts: make(map[string]*account),
+		tokens:   make(map[string]tokenInfo),
+		replies:  make(map[string]reply),
+		users:    newCollection[goapi.User](),
+		tenants:  make(map[string]*tenantData),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a goapi.Client talking to the server
+func (s *Server) Client(opts ...goapi.Option) *goapi.Client {
+	return goapi.New(s.URL, append([]goapi.Option{goapi.WithHTTPClient(s.srv.Client())}, opts...)...)
+}
+
+// Account used by NewTestClient
+const (
+	TestEmail    = "coach@example.com"
+	TestPassword = "secret"
+)
+
+// NewTestClient starts a server closed at the end of tb, adds a TestEmail
+// account with a profile in tenant and returns a client logged in to it as
+// tenant. The client renews its login with the account's credentials and
+// retries back off for a millisecond only; opts may override either.
+func NewTestClient(tb testing.TB, tenant string, opts ...goapi.Option) (*Server, *goapi.Client) {
+	tb.Helper()
+	s := NewServer()
+	tb.Cleanup(s.Close)
+	s.AddAccount(TestEmail, TestPassword, tenant)
+
+	policy := goapi.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client := s.Client(append([]goapi.Option{
+		goapi.WithTenantName(tenant),
+		goapi.WithCredentials(goapi.StaticCredentials(TestEmail, TestPassword)),
+		goapi.WithRetryPolicy(policy),
+	}, opts...)...)
+	if err := client.Login(TestEmail, TestPassword); err != nil {
+		tb.Fatal(err)
+	}
+	return s, client
+}
+
+// AddAccount creates a user that can log in with email and password and
+// receives a token for each of tenants
+func (s *Server) AddAccount(email, password string, tenants ...string) goapi.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timestamp()
+	user := goapi.User{
+		ID:    s.newID("usr"),
+		Email: email,
+		Name:  email,
+		DateFields: goapi.DateFields{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+		},
+	}
+	for _, tenant := range tenants {
+		s.tenant(tenant)
+		user.Profiles = append(user.Profiles, goapi.UserProfile{
+			ID:       s.newID("prof"),
+			UserID:   user.ID,
+			TenantID: tenant,
+			IsActive: true,
+		})
+	}
+	s.users.put(user.ID, user)
+	s.accounts[email] = &account{password: password, userID: user.ID, tenants: tenants}
+	return user
+}
+
+// ExpireTokens invalidates every token issued so far, as if they had all
+// expired
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]tokenInfo)
+}
+
+// InjectFault adds a fault. Faults are matched in the order they were added.
+func (s *Server) InjectFault(fault *Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, fault)
+}
+
+// ClearFaults removes all faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received so far for method and path
+func (s *Server) RequestsTo(method, path string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matching []Request
+	for _, req := range s.requests {
+		if req.Method == method && req.Path == path {
+			matching = append(matching, req)
+		}
+	}
+	return matching
+}
+
+// tenant returns the data of a tenant, creating it on first use
+func (s *Server) tenant(name string) *tenantData {
+	t, ok := s.tenants[name]
+	if !ok {
+		t = &tenantData{
+			timeSheets:     newCollection[goapi.ProfileTimeSheet](),
+			reimbursements: newCollection[goapi.ProfileReimbursement](),
+			products:       newCollection[goapi.Product](),
+			schedules:      newCollection[goapi.ProductSchedule](),
+			sessions:       newCollection[goapi.ProductScheduleSession](),
+		}
+		s.tenants[name] = t
+	}
+	return t
+}
+
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s_%d", prefix, s.nextID)
+}
+
+func (s *Server) timestamp() goapi.Timestamp {
+	return goapi.NewTimestamp(s.Now().UTC())
+}
+
+// issueTokens returns a fresh token for every tenant of acct
+func (s *Server) issueTokens(email string, acct *account) map[string]string {
+	tokens := make(map[string]string, len(acct.tenants))
+	expiry := s.Now().Add(s.TokenTTL)
+	for _, tenant := range acct.tenants {
+		s.nextID++
+		claims, _ := json.Marshal(map[string]interface{}{
+			"sub":    acct.userID,
+			"tenant": tenant,
+			"exp":    expiry.Unix(),
+			"jti":    s.nextID,
+		})
+		token := "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + base64.RawURLEncoding.EncodeToString(claims) + "."
+		s.tokens[token] = tokenInfo{email: email, tenant: tenant, expiry: expiry}
+		tokens[tenant] = token
+	}
+	return tokens
+}
+
+// collection keeps records by ID in insertion order
+type collection[T any] struct {
+	records map[string]T
+	order   []string
+}
+
+func newCollection[T any]() *collection[T] {
+	return &collection[T]{records: make(map[string]T)}
+}
+
+func (c *collection[T]) get(id string) (T, bool) {
+	record, ok := c.records[id]
+	return record, ok
+}
+
+func (c *collection[T]) put(id string, record T) {
+	if _, ok := c.records[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	c.records[id] = record
+}
+
+func (c *collection[T]) delete(id string) bool {
+	if _, ok := c.records[id]; !ok {
+		return false
+	}
+	delete(c.records, id)
+	for i, existing := range c.order {
+		if existing == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (c *collection[T]) list(keep func(T) bool) []T {
+	records := make([]T, 0, len(c.order))
+	for _, id := range c.order {
+		if record := c.records[id]; keep == nil || keep(record) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// paginate answers a list request, as a bare array unless the client asked
+// for pages with page_size
+func paginate[T any](w http.ResponseWriter, r *http.Request, records []T) {
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		writeJSON(w, http.StatusOK, records)
+		return
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+	if start < 0 || start > len(records) {
+		start = len(records)
+	}
+	end := start + pageSize
+	if end > len(records) {
+		end = len(records)
+	}
+	page := map[string]interface{}{"data": records[start:end]}
+	if end < len(records) {
+		page["next_cursor"] = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string, fieldErrors ...goapi.FieldError) {
+	body := map[string]interface{}{
+		"error": map[string]string{"code": code, "message": message},
+	}
+	if len(fieldErrors) > 0 {
+		body["errors"] = fieldErrors
+	}
+	writeJSON(w, status, body)
+}
+
+func matchesFault(f *Fault, r *http.Request) bool {
+	return (f.Method == "" || strings.EqualFold(f.Method, r.Method)) &&
+		strings.HasPrefix(r.URL.Path, f.Path) &&
+		(f.Times == 0 || f.hits < f.Times)
+}
@@ -0,0 +1,123 @@
+package goapitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+// newClient returns a fake server, a client logged in to it and the ID of
+// the client's profile
+func newClient(t *testing.T) (*goapitest.Server, *goapi.Client, string) {
+	t.Helper()
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, client, users[0].Profiles[0].ID
+}
+
+func statusOf(err error) int {
+	var apiErr *goapi.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestIdempotencyKeyReplaysResponse(t *testing.T) {
+	srv, client, profileID := newClient(t)
+	ctx := goapi.WithIdempotencyKey(context.Background(), "receipt-7")
+	reimbursement := goapi.ProfileReimbursement{UserProfileID: profileID, Amount: goapi.MustParseMoney("12.50")}
+
+	first, err := client.CreateReimbursementContext(ctx, reimbursement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.CreateReimbursementContext(ctx, reimbursement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("replayed ID = %s, want %s", second.ID, first.ID)
+	}
+	stored, err := client.GetReimbursements(profileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("stored %d reimbursements, want 1", len(stored))
+	}
+
+	reimbursement.Amount = goapi.MustParseMoney("99.00")
+	if _, err := client.CreateReimbursementContext(ctx, reimbursement); statusOf(err) != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with a different body: err = %v, want a 422", err)
+	}
+
+	// Every call reached the server; the second was answered from the stored reply
+	posts := srv.RequestsTo(http.MethodPost, "/reimbursements")
+	if len(posts) != 3 || posts[1].Status != http.StatusCreated {
+		t.Errorf("POST requests = %+v, want 3 with a replayed 201 second", posts)
+	}
+}
+
+func TestProcessedFaultWithoutKeyIsNotRetried(t *testing.T) {
+	srv, client, profileID := newClient(t)
+	srv.InjectFault(&goapitest.Fault{
+		Method:    http.MethodPost,
+		Path:      "/reimbursements",
+		Status:    http.StatusBadGateway,
+		Times:     1,
+		Processed: true,
+	})
+
+	_, err := client.CreateReimbursement(goapi.ProfileReimbursement{UserProfileID: profileID})
+	if statusOf(err) != http.StatusBadGateway {
+		t.Fatalf("err = %v, want a 502", err)
+	}
+	// The server stored the reimbursement even though the client saw a 502
+	stored, err := client.GetReimbursements(profileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("stored %d reimbursements, want 1", len(stored))
+	}
+}
+
+func TestClockInTwiceConflicts(t *testing.T) {
+	_, client, profileID := newClient(t)
+
+	if _, err := client.ClockIn(profileID, goapi.ProfileTimeSheet{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ClockIn(profileID, goapi.ProfileTimeSheet{}); statusOf(err) != http.StatusConflict {
+		t.Errorf("second clock in: err = %v, want a 409", err)
+	}
+	if _, err := client.ClockOut(profileID, goapi.ProfileTimeSheet{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ClockOut(profileID, goapi.ProfileTimeSheet{}); statusOf(err) != http.StatusConflict {
+		t.Errorf("clock out while clocked out: err = %v, want a 409", err)
+	}
+}
+
+func TestDeletedProductIsGone(t *testing.T) {
+	_, client, _ := newClient(t)
+	product, err := client.CreateProduct(goapi.Product{Name: "Yoga"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteProduct(product.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetProduct(product.ID); statusOf(err) != http.StatusNotFound {
+		t.Errorf("get after delete: err = %v, want a 404", err)
+	}
+}
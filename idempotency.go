@@ -0,0 +1,72 @@
+package goapi
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of a
+// mutating request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx that makes every mutating call
+// made with it send key as its Idempotency-Key, e.g. a key derived from a
+// kiosk event ID so that the same clock-in is never recorded twice. The
+// server treats calls sharing a key as repeats of the first one, so derive a
+// new ctx for each distinct call rather than reusing one across calls.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by WithIdempotencyKey
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// WithAutoIdempotencyKeys controls whether the client generates an
+// Idempotency-Key for mutating calls that were not given one. It is enabled
+// by default.
+func WithAutoIdempotencyKeys(enabled bool) Option {
+	return func(c *Client) {
+		c.autoIdempotencyKeys = enabled
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// idempotencyKey picks the key to send with a request, or "" for none. The
+// same key is reused for every retry of the request.
+func (c *Client) idempotencyKey(ctx context.Context, method string) (string, error) {
+	if !isMutating(method) {
+		return "", nil
+	}
+	if key, ok := IdempotencyKeyFromContext(ctx); ok {
+		return key, nil
+	}
+	if !c.autoIdempotencyKeys {
+		return "", nil
+	}
+	return newIdempotencyKey()
+}
+
+// newIdempotencyKey returns a random UUID (version 4)
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("error generating idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
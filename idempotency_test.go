@@ -0,0 +1,134 @@
+package goapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+func TestPostRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		callerKey    string
+		keyedRetries bool
+		wantAttempts int
+	}{
+		{name: "generated key", wantAttempts: 1},
+		{name: "caller key", callerKey: "kiosk-42", wantAttempts: 2},
+		{name: "generated key opted in", keyedRetries: true, wantAttempts: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var keys []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				keys = append(keys, r.Header.Get(goapi.IdempotencyKeyHeader))
+				if len(keys) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Write([]byte(`{"id":"rb_1"}`))
+			}))
+			defer srv.Close()
+
+			policy := goapi.DefaultRetryPolicy()
+			policy.InitialBackoff = time.Millisecond
+			policy.RetryKeyedMutations = tt.keyedRetries
+			client := goapi.New(srv.URL, goapi.WithRetryPolicy(policy))
+
+			ctx := context.Background()
+			if tt.callerKey != "" {
+				ctx = goapi.WithIdempotencyKey(ctx, tt.callerKey)
+			}
+			client.CreateReimbursementContext(ctx, goapi.ProfileReimbursement{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(keys) != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", len(keys), tt.wantAttempts)
+			}
+			if keys[0] == "" || keys[len(keys)-1] != keys[0] {
+				t.Errorf("keys = %q, want one key reused across attempts", keys)
+			}
+			if tt.callerKey != "" && keys[0] != tt.callerKey {
+				t.Errorf("key = %q, want %q", keys[0], tt.callerKey)
+			}
+		})
+	}
+}
+
+func TestCallerKeyDeduplicatesRetry(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	// The first reimbursement is stored but its response is lost
+	srv.InjectFault(&goapitest.Fault{
+		Method:    http.MethodPost,
+		Path:      "/reimbursements",
+		Status:    http.StatusBadGateway,
+		Times:     1,
+		Processed: true,
+	})
+
+	reimbursement := goapi.ProfileReimbursement{
+		UserProfileID: "prof_1",
+		Date:          goapi.Date{Year: 2024, Month: 3, Day: 1},
+		Amount:        goapi.MustParseMoney("12.50"),
+	}
+	ctx := goapi.WithIdempotencyKey(context.Background(), "receipt-7")
+	created, err := client.CreateReimbursementContext(ctx, reimbursement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []int
+	for _, r := range srv.RequestsTo(http.MethodPost, "/reimbursements") {
+		statuses = append(statuses, r.Status)
+	}
+	if len(statuses) != 2 || statuses[0] != 502 || statuses[1] != 201 {
+		t.Errorf("statuses = %v, want [502 201]", statuses)
+	}
+
+	stored, err := client.GetReimbursements("prof_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].ID != created.ID {
+		t.Errorf("stored = %+v, want only %s", stored, created.ID)
+	}
+}
+
+func TestCallerKeyAppliesToEveryCall(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	first := goapi.ProfileReimbursement{UserProfileID: "prof_1", Date: goapi.Date{Year: 2024, Month: 3, Day: 1}, Amount: goapi.MustParseMoney("12.50")}
+	second := goapi.ProfileReimbursement{UserProfileID: "prof_1", Date: goapi.Date{Year: 2024, Month: 3, Day: 2}, Amount: goapi.MustParseMoney("8")}
+
+	// Two different calls sharing a ctx send the same key
+	shared := goapi.WithIdempotencyKey(context.Background(), "receipt-7")
+	if _, err := client.CreateReimbursementContext(shared, first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateReimbursementContext(shared, second); !errors.Is(err, goapi.ErrValidation) {
+		t.Errorf("second call with the same key = %v, want a 422 for the reused key", err)
+	}
+
+	// A ctx derived per call keeps them apart
+	ctx := goapi.WithIdempotencyKey(context.Background(), "receipt-8")
+	if _, err := client.CreateReimbursementContext(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	requests := srv.RequestsTo(http.MethodPost, "/reimbursements")
+	var keys []string
+	for _, r := range requests {
+		keys = append(keys, r.Header.Get(goapi.IdempotencyKeyHeader))
+	}
+	if len(keys) != 3 || keys[0] != "receipt-7" || keys[1] != "receipt-7" || keys[2] != "receipt-8" {
+		t.Errorf("keys = %q, want receipt-7 twice then receipt-8", keys)
+	}
+}
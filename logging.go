@@ -0,0 +1,206 @@
+package goapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// secretFields are never logged or recorded
+var secretFields = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"tokens":        true,
+	"access_token":  true,
+	"refresh_token": true,
+	"client_secret": true,
+}
+
+// piiFields are logged only when WithLogPII is enabled
+var piiFields = map[string]bool{
+	"email":      true,
+	"user_email": true,
+	"lat_in":     true,
+	"lng_in":     true,
+	"lat_out":    true,
+	"lng_out":    true,
+}
+
+// IsSecretField reports whether a JSON or form field named name holds a
+// secret, such as a password or token, that must never be logged or
+// recorded. The comparison ignores case.
+func IsSecretField(name string) bool {
+	return secretFields[strings.ToLower(name)]
+}
+
+// WithLogger makes the client log every call, including logins and token
+// refreshes, to logger: successful calls at debug level with operation,
+// method, path, status, latency and retry count, failed calls at warn level,
+// and retries at debug level
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogBodies adds request bodies to the debug logs. Passwords and tokens
+// are always redacted; personal data is redacted unless WithLogPII is set.
+func WithLogBodies(enabled bool) Option {
+	return func(c *Client) {
+		c.logBodies = enabled
+	}
+}
+
+// WithLogPII stops redacting personal data such as emails and clock-in
+// coordinates from logged bodies
+func WithLogPII(enabled bool) Option {
+	return func(c *Client) {
+		c.logPII = enabled
+	}
+}
+
+func (c *Client) logCall(ctx context.Context, method, path string, requestBody []byte, resp *http.Response, err error, retries int, latency time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", OperationFromContext(ctx)),
+		slog.String("method", method),
+		slog.String("path", path),
+	}
+
+	var apiErr *APIError
+	switch {
+	case resp != nil:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	case errors.As(err, &apiErr):
+		attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+		if apiErr.RequestID != "" {
+			attrs = append(attrs, slog.String("request_id", apiErr.RequestID))
+		}
+	}
+	attrs = append(attrs,
+		slog.Duration("latency", latency),
+		slog.Int("retries", retries),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if c.logBodies && len(requestBody) > 0 {
+		attrs = append(attrs, slog.String("request_body", c.redactBody(requestBody)))
+	}
+	c.logger.LogAttrs(ctx, level, "goapi request", attrs...)
+}
+
+func (c *Client) logRetry(ctx context.Context, event RetryEvent) {
+	if c.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", OperationFromContext(ctx)),
+		slog.String("method", event.Method),
+		slog.String("path", event.Path),
+		slog.Int("attempt", event.Attempt),
+		slog.Duration("delay", event.Delay),
+	}
+	if event.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", event.StatusCode))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "goapi retry", attrs...)
+}
+
+// redactBody returns a JSON body with secrets, and personal data unless
+// enabled, replaced by a placeholder
+func (c *Client) redactBody(body []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return redacted
+	}
+	redactValue(value, c.logPII)
+	redactedBody, err := json.Marshal(value)
+	if err != nil {
+		return redacted
+	}
+	return string(redactedBody)
+}
+
+func redactValue(value interface{}, keepPII bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if IsSecretField(key) || (!keepPII && piiFields[key]) {
+				if field != nil && field != "" {
+					v[key] = redacted
+				}
+				continue
+			}
+			redactValue(field, keepPII)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item, keepPII)
+		}
+	}
+}
+
+// LogValue keeps the password and email out of logs when a User is logged
+// with slog
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", u.ID),
+		slog.String("name", u.Name),
+	)
+}
+
+// LogValue keeps the email and clock-in coordinates out of logs when a
+// ProfileTimeSheet is logged with slog
+func (t ProfileTimeSheet) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", t.ID),
+		slog.String("user_profile_id", t.UserProfileID),
+		slog.String("time_in", t.TimeIn.String()),
+	}
+	if t.TimeOut != nil {
+		attrs = append(attrs, slog.String("time_out", t.TimeOut.String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue keeps the email out of logs when a ProfileReimbursement is logged
+// with slog
+func (r ProfileReimbursement) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", r.ID),
+		slog.String("user_profile_id", r.UserProfileID),
+		slog.String("date", r.Date.String()),
+		slog.String("amount", r.Amount.String()),
+		slog.String("status", string(r.Status)),
+	)
+}
+
+// LogValue keeps the credentials out of logs when a LoginRequest is logged
+// with slog
+func (r LoginRequest) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("user", r.User))
+}
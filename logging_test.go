@@ -0,0 +1,119 @@
+package goapi_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+const (
+	testLat = "52.370216"
+	testLng = "4.895168"
+)
+
+func TestLogRedactsSecretsAndPII(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	_, client := goapitest.NewTestClient(t, "gym-north", goapi.WithLogger(logger), goapi.WithLogBodies(true))
+	token, err := client.GetJWT()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateUser(goapi.User{Name: "Ana", Email: "ana@example.com", Password: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := goapi.ProfileTimeSheet{UserEmail: "coach@example.com", LatIn: testLat, LngIn: testLng}
+	if _, err := client.ClockIn(users[0].Profiles[0].ID, sheet); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := out.String()
+	for _, secret := range []string{goapitest.TestPassword, token, "hunter2", "ana@example.com", goapitest.TestEmail, testLat, testLng} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, logs)
+		}
+	}
+	for _, want := range []string{
+		`"operation":"Login"`,
+		`"operation":"CreateUser"`,
+		`"operation":"ClockIn"`,
+		`\"password\":\"[REDACTED]\"`,
+		`\"user_email\":\"[REDACTED]\"`,
+		`\"lat_in\":\"[REDACTED]\"`,
+		`\"lng_in\":\"[REDACTED]\"`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs lack %s:\n%s", want, logs)
+		}
+	}
+}
+
+func TestLogPIIOptIn(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	_, client := goapitest.NewTestClient(t, "gym-north", goapi.WithLogger(logger), goapi.WithLogBodies(true), goapi.WithLogPII(true))
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ClockIn(users[0].Profiles[0].ID, goapi.ProfileTimeSheet{LatIn: testLat}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := out.String()
+	if !strings.Contains(logs, testLat) {
+		t.Errorf("logs lack the coordinates:\n%s", logs)
+	}
+	if strings.Contains(logs, goapitest.TestPassword) {
+		t.Errorf("logs contain the password:\n%s", logs)
+	}
+}
+
+func TestLogFailedLogin(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil))
+	_, client := goapitest.NewTestClient(t, "gym-north", goapi.WithLogger(logger))
+	out.Reset()
+
+	if err := client.Login(goapitest.TestEmail, "wrong"); err == nil {
+		t.Fatal("login with a wrong password succeeded")
+	}
+	logs := out.String()
+	for _, want := range []string{`"level":"WARN"`, `"operation":"Login"`, `"path":"/login"`, `"status":401`} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs lack %s:\n%s", want, logs)
+		}
+	}
+}
+
+func TestLogValuesHidePII(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	logger.Info("models",
+		"user", goapi.User{ID: "usr_1", Email: "ana@example.com", Password: "hunter2"},
+		"sheet", goapi.ProfileTimeSheet{ID: "ts_1", UserEmail: "ana@example.com", LatIn: testLat, LngIn: testLng},
+		"reimbursement", goapi.ProfileReimbursement{ID: "rb_1", UserEmail: "ana@example.com"},
+		"login", goapi.LoginRequest{User: goapi.User{Email: "ana@example.com", Password: "hunter2"}},
+	)
+
+	logs := out.String()
+	for _, secret := range []string{"hunter2", "ana@example.com", testLat, testLng} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q: %s", secret, logs)
+		}
+	}
+	for _, want := range []string{"user.id=usr_1", "sheet.id=ts_1", "reimbursement.id=rb_1"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs lack %s: %s", want, logs)
+		}
+	}
+}
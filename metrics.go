@@ -0,0 +1,243 @@
+package goapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements from the client. Implementations must be
+// safe for concurrent use.
+type Metrics interface {
+	// RequestDone is called once per API call after all retries, including
+	// the Login and RefreshToken calls the client makes. status is 0 when no
+	// response was received.
+	RequestDone(operation string, status int, err error, latency time.Duration)
+
+	// Retried is called before every retry of an API call
+	Retried(operation string)
+
+	// TokenRefreshed is called whenever the client logs in or refreshes its
+	// token by itself while serving operation
+	TokenRefreshed(operation string, err error)
+}
+
+// WithMetrics makes the client report into metrics
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// statusClass groups a status code as "2xx", "4xx", ..., or "error" when no
+// response was received
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// DefaultLatencyBuckets are the latency histogram bounds in seconds used by
+// NewPrometheusMetrics when none are given
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is an in-memory Metrics implementation that serves its
+// counters and histograms in the Prometheus text exposition format
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu        sync.Mutex // Mutex for thread-safe access to the maps below
+	requests  map[[2]string]uint64
+	errors    map[[2]string]uint64
+	latencies map[string]*histogram
+	retries   map[string]uint64
+	refreshes map[[2]string]uint64
+}
+
+type histogram struct {
+	counts []uint64 // One per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics returns an empty PrometheusMetrics with the given
+// latency buckets in seconds, or DefaultLatencyBuckets
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		requests:  make(map[[2]string]uint64),
+		errors:    make(map[[2]string]uint64),
+		latencies: make(map[string]*histogram),
+		retries:   make(map[string]uint64),
+		refreshes: make(map[[2]string]uint64),
+	}
+}
+
+func (m *PrometheusMetrics) RequestDone(operation string, status int, err error, latency time.Duration) {
+	key := [2]string{operation, statusClass(status)}
+	seconds := latency.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[key]++
+	if err != nil {
+		m.errors[key]++
+	}
+	h, ok := m.latencies[operation]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[operation] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (m *PrometheusMetrics) Retried(operation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[operation]++
+}
+
+func (m *PrometheusMetrics) TokenRefreshed(operation string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshes[[2]string{operation, result}]++
+}
+
+// ServeHTTP renders the metrics for a Prometheus scrape
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format. A
+// slow writer does not hold up the API calls reporting into m.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	m.render(&buf)
+	return buf.WriteTo(w)
+}
+
+// render formats a snapshot of the metrics into w
+func (m *PrometheusMetrics) render(w *bytes.Buffer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeHeader(w, "goapi_requests_total", "counter", "API calls made, by operation and status class.")
+	for _, key := range sortedPairs(m.requests) {
+		fmt.Fprintf(w, "goapi_requests_total{operation=%s,status_class=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.requests[key])
+	}
+
+	writeHeader(w, "goapi_request_errors_total", "counter", "API calls that returned an error, by operation and status class.")
+	for _, key := range sortedPairs(m.errors) {
+		fmt.Fprintf(w, "goapi_request_errors_total{operation=%s,status_class=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.errors[key])
+	}
+
+	writeHeader(w, "goapi_request_duration_seconds", "histogram", "Latency of API calls including retries, by operation.")
+	for _, operation := range sortedKeys(m.latencies) {
+		h := m.latencies[operation]
+		label := quoteLabel(operation)
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "goapi_request_duration_seconds_bucket{operation=%s,le=\"%s\"} %d\n", label, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(w, "goapi_request_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(w, "goapi_request_duration_seconds_sum{operation=%s} %s\n", label, formatFloat(h.sum))
+		fmt.Fprintf(w, "goapi_request_duration_seconds_count{operation=%s} %d\n", label, h.count)
+	}
+
+	writeHeader(w, "goapi_retries_total", "counter", "Retries of API calls, by operation.")
+	for _, operation := range sortedKeys(m.retries) {
+		fmt.Fprintf(w, "goapi_retries_total{operation=%s} %d\n", quoteLabel(operation), m.retries[operation])
+	}
+
+	writeHeader(w, "goapi_token_refreshes_total", "counter", "Logins and token refreshes made by the client, by operation and result.")
+	for _, key := range sortedPairs(m.refreshes) {
+		fmt.Fprintf(w, "goapi_token_refreshes_total{operation=%s,result=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.refreshes[key])
+	}
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func quoteLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// recordRequest reports a finished API call to the client's metrics
+func (c *Client) recordRequest(operation string, resp *http.Response, err error, latency time.Duration) {
+	if c.metrics == nil {
+		return
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	} else if apiErr := (*APIError)(nil); errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	c.metrics.RequestDone(operation, status, err, latency)
+}
+
+// recordTokenRefresh reports a login or refresh made on behalf of the
+// operation in ctx
+func (c *Client) recordTokenRefresh(ctx context.Context, err error) {
+	if c.metrics != nil {
+		c.metrics.TokenRefreshed(OperationFromContext(ctx), err)
+	}
+}
@@ -0,0 +1,116 @@
+package goapi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+func TestPrometheusExposition(t *testing.T) {
+	m := goapi.NewPrometheusMetrics(0.1, 1)
+	m.RequestDone("GetUsers", 200, nil, 50*time.Millisecond)
+	m.RequestDone("GetUsers", 200, nil, 500*time.Millisecond)
+	m.RequestDone("GetUsers", 503, errors.New("unavailable"), 2*time.Second)
+	m.RequestDone(`Odd"Op`, 0, errors.New("refused"), 0)
+	m.Retried("GetUsers")
+	m.TokenRefreshed("GetUsers", nil)
+
+	var out strings.Builder
+	n, err := m.WriteTo(&out)
+	if err != nil || n != int64(out.Len()) {
+		t.Fatalf("WriteTo = %d, %v; wrote %d bytes", n, err, out.Len())
+	}
+	want := `# HELP goapi_requests_total API calls made, by operation and status class.
+# TYPE goapi_requests_total counter
+goapi_requests_total{operation="GetUsers",status_class="2xx"} 2
+goapi_requests_total{operation="GetUsers",status_class="5xx"} 1
+goapi_requests_total{operation="Odd\"Op",status_class="error"} 1
+# HELP goapi_request_errors_total API calls that returned an error, by operation and status class.
+# TYPE goapi_request_errors_total counter
+goapi_request_errors_total{operation="GetUsers",status_class="5xx"} 1
+goapi_request_errors_total{operation="Odd\"Op",status_class="error"} 1
+# HELP goapi_request_duration_seconds Latency of API calls including retries, by operation.
+# TYPE goapi_request_duration_seconds histogram
+goapi_request_duration_seconds_bucket{operation="GetUsers",le="0.1"} 1
+goapi_request_duration_seconds_bucket{operation="GetUsers",le="1"} 2
+goapi_request_duration_seconds_bucket{operation="GetUsers",le="+Inf"} 3
+goapi_request_duration_seconds_sum{operation="GetUsers"} 2.55
+goapi_request_duration_seconds_count{operation="GetUsers"} 3
+goapi_request_duration_seconds_bucket{operation="Odd\"Op",le="0.1"} 1
+goapi_request_duration_seconds_bucket{operation="Odd\"Op",le="1"} 1
+goapi_request_duration_seconds_bucket{operation="Odd\"Op",le="+Inf"} 1
+goapi_request_duration_seconds_sum{operation="Odd\"Op"} 0
+goapi_request_duration_seconds_count{operation="Odd\"Op"} 1
+# HELP goapi_retries_total Retries of API calls, by operation.
+# TYPE goapi_retries_total counter
+goapi_retries_total{operation="GetUsers"} 1
+# HELP goapi_token_refreshes_total Logins and token refreshes made by the client, by operation and result.
+# TYPE goapi_token_refreshes_total counter
+goapi_token_refreshes_total{operation="GetUsers",result="success"} 1
+`
+	if out.String() != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+// blockingWriter stands in for a scraper that stops reading
+type blockingWriter struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	close(w.entered)
+	<-w.release
+	return len(p), nil
+}
+
+func TestPrometheusSlowScrapeDoesNotBlockCalls(t *testing.T) {
+	m := goapi.NewPrometheusMetrics()
+	m.RequestDone("GetUsers", 200, nil, time.Millisecond)
+	w := &blockingWriter{entered: make(chan struct{}), release: make(chan struct{})}
+	defer close(w.release)
+	go m.WriteTo(w)
+	<-w.entered
+
+	done := make(chan struct{})
+	go func() {
+		m.RequestDone("GetUsers", 200, nil, time.Millisecond)
+		m.Retried("GetUsers")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RequestDone blocked behind a slow scrape")
+	}
+}
+
+func TestMetricsCountLogins(t *testing.T) {
+	m := goapi.NewPrometheusMetrics()
+	srv, client := goapitest.NewTestClient(t, "gym-north", goapi.WithMetrics(m))
+	if err := client.Login(goapitest.TestEmail, "wrong"); err == nil {
+		t.Fatal("login with a wrong password succeeded")
+	}
+	srv.ExpireTokens()
+	if _, err := client.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	m.WriteTo(&out)
+	for _, line := range []string{
+		`goapi_requests_total{operation="Login",status_class="2xx"} 2`,
+		`goapi_requests_total{operation="Login",status_class="4xx"} 1`,
+		`goapi_requests_total{operation="GetProducts",status_class="2xx"} 1`,
+		`goapi_token_refreshes_total{operation="GetProducts",result="success"} 1`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("exposition lacks %s:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package goapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler sends a single attempt of an API call
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler to observe or change outgoing requests and
+// incoming responses, e.g. to add correlation IDs or record audit logs
+type Middleware func(next Handler) Handler
+
+// WithMiddleware appends middleware to the client. Middleware runs around
+// every attempt of a call, including retries and the replay after a
+// re-authentication, and sees the request after authentication headers are
+// set. The first middleware added is the outermost: it sees the request
+// first and the response last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+type operationContextKey struct{}
+
+// withOperation records the name of the API call being made
+func withOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation)
+}
+
+// OperationFromContext returns the name of the API call a request belongs
+// to, such as "CreateTimeSheet" or "Login". Middleware reads it from the
+// request context.
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationContextKey{}).(string)
+	return operation
+}
+
+// buildHandler chains the client's middleware around the HTTP client
+func (c *Client) buildHandler() Handler {
+	handler := Handler(c.httpClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	return handler
+}
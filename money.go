@@ -0,0 +1,414 @@
+package goapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// RoundingMode selects how Money is rounded to fewer decimal places
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to nearest, ties away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to nearest, ties to the even neighbor (banker's
+	// rounding)
+	RoundHalfEven
+	// RoundHalfDown rounds to nearest, ties toward zero
+	RoundHalfDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundDown rounds toward zero (truncates)
+	RoundDown
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+)
+
+// Errors returned by Money arithmetic
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrDivisionByZero   = errors.New("division by zero")
+)
+
+// Money is an exact decimal amount with an optional ISO 4217 currency code.
+// On the wire it is a JSON number written with all its decimal places, as
+// the API expects. Decoding also accepts strings such as "12.50" or
+// "12.50 USD"; amounts decoded from a string with a currency, or marked with
+// Labeled, encode back to that string form so that nothing is lost.
+//
+// Money values are immutable and the zero value is 0. Compare them with
+// Compare or Equal rather than ==. Arithmetic on amounts in two different
+// currencies fails with ErrCurrencyMismatch; an amount without currency
+// combines with any other.
+type Money struct {
+	coef     *big.Int // Amount in units of 10^-scale, nil for zero
+	scale    int32
+	currency string
+	labeled  bool // Encode as a string with the currency
+}
+
+var (
+	numberPattern   = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*))?(?:[eE]([+-]?\d+))?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// NewMoney returns units*10^-scale in currency, e.g. NewMoney(1250, 2,
+// "USD") is 12.50 USD. currency may be empty.
+func NewMoney(units int64, scale int32, currency string) Money {
+	if scale < 0 {
+		return Money{coef: new(big.Int).Mul(big.NewInt(units), pow10(-scale)), currency: currency}
+	}
+	return Money{coef: big.NewInt(units), scale: scale, currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "12.50", "-3", "1e-2",
+// "12.50 USD" or "USD 12.50"
+func ParseMoney(s string) (Money, error) {
+	fields := strings.Fields(s)
+	var currency string
+	switch {
+	case len(fields) == 2 && currencyPattern.MatchString(fields[1]):
+		currency = fields[1]
+		fields = fields[:1]
+	case len(fields) == 2 && currencyPattern.MatchString(fields[0]):
+		currency = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	m, err := parseDecimal(fields[0])
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	m.currency = currency
+	return m, nil
+}
+
+// MustParseMoney is like ParseMoney but panics on invalid input. It is meant
+// for constants.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func parseDecimal(s string) (Money, error) {
+	match := numberPattern.FindStringSubmatch(s)
+	if match == nil || match[2]+match[3] == "" {
+		return Money{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	coef, ok := new(big.Int).SetString(match[1]+match[2]+match[3], 10)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	scale := int64(len(match[3]))
+	if match[4] != "" {
+		var exp int64
+		if _, err := fmt.Sscan(match[4], &exp); err != nil || exp > 1<<20 || exp < -(1<<20) {
+			return Money{}, fmt.Errorf("invalid exponent in %q", s)
+		}
+		scale -= exp
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Money{coef: coef, scale: int32(scale)}, nil
+}
+
+// Currency returns the ISO 4217 currency code of m, or "" if it has none
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// WithCurrency returns m in currency
+func (m Money) WithCurrency(currency string) Money {
+	m.currency = currency
+	return m
+}
+
+// Labeled returns m set to encode to JSON as a string with its currency,
+// such as "12.50 USD", instead of a number. Send labeled amounts only to
+// endpoints or stores that accept that form.
+func (m Money) Labeled() Money {
+	m.labeled = true
+	return m
+}
+
+// Scale returns the number of decimal places of m
+func (m Money) Scale() int32 {
+	return m.scale
+}
+
+// Sign returns -1, 0 or +1 as m is negative, zero or positive
+func (m Money) Sign() int {
+	if m.coef == nil {
+		return 0
+	}
+	return m.coef.Sign()
+}
+
+// IsZero reports whether m is zero
+func (m Money) IsZero() bool {
+	return m.Sign() == 0
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other, "add")
+	if err != nil {
+		return Money{}, err
+	}
+	scale := max(m.scale, other.scale)
+	coef := new(big.Int).Add(m.rescaled(scale), other.rescaled(scale))
+	return Money{coef: coef, scale: scale, currency: currency, labeled: m.labeled || other.labeled}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other, "subtract")
+	if err != nil {
+		return Money{}, err
+	}
+	scale := max(m.scale, other.scale)
+	coef := new(big.Int).Sub(m.rescaled(scale), other.rescaled(scale))
+	return Money{coef: coef, scale: scale, currency: currency, labeled: m.labeled || other.labeled}, nil
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	m.coef = new(big.Int).Neg(m.int())
+	return m
+}
+
+// Abs returns |m|
+func (m Money) Abs() Money {
+	m.coef = new(big.Int).Abs(m.int())
+	return m
+}
+
+// Mul returns m * factor exactly, with the decimal places of both
+func (m Money) Mul(factor Money) (Money, error) {
+	currency, err := m.commonCurrency(factor, "multiply")
+	if err != nil {
+		return Money{}, err
+	}
+	coef := new(big.Int).Mul(m.int(), factor.int())
+	return Money{coef: coef, scale: m.scale + factor.scale, currency: currency, labeled: m.labeled || factor.labeled}, nil
+}
+
+// MulInt returns m * n
+func (m Money) MulInt(n int64) Money {
+	m.coef = new(big.Int).Mul(m.int(), big.NewInt(n))
+	return m
+}
+
+// Div returns m / divisor rounded to scale decimal places with mode. A
+// negative scale rounds to tens, hundreds and so on.
+func (m Money) Div(divisor Money, scale int32, mode RoundingMode) (Money, error) {
+	if divisor.IsZero() {
+		return Money{}, fmt.Errorf("cannot divide %s: %w", m, ErrDivisionByZero)
+	}
+	currency, err := m.commonCurrency(divisor, "divide")
+	if err != nil {
+		return Money{}, err
+	}
+	if divisor.currency != "" && divisor.currency == m.currency {
+		// Amount divided by amount is a plain ratio
+		currency = ""
+	}
+	// m/divisor = m.coef*10^divisor.scale / (divisor.coef*10^m.scale)
+	num := new(big.Int).Mul(m.int(), pow10(divisor.scale+max(scale, 0)))
+	den := new(big.Int).Mul(divisor.int(), pow10(m.scale+max(-scale, 0)))
+	coef, scale := unscaled(roundQuo(num, den, mode), scale)
+	return Money{coef: coef, scale: scale, currency: currency, labeled: m.labeled || divisor.labeled}, nil
+}
+
+// Round returns m rounded to scale decimal places with mode. Rounding to
+// more places than m has only pads it with zeros, and a negative scale
+// rounds to tens, hundreds and so on.
+func (m Money) Round(scale int32, mode RoundingMode) Money {
+	if scale >= m.scale {
+		m.coef = m.rescaled(scale)
+		m.scale = scale
+		return m
+	}
+	m.coef, m.scale = unscaled(roundQuo(m.int(), pow10(m.scale-scale), mode), scale)
+	return m
+}
+
+// Compare returns -1, 0 or +1 as m sorts before, with or after other.
+// Amounts are ordered by currency code first (no currency sorts first) and
+// then by value; trailing zeros do not matter: 12.5 equals 12.50.
+func (m Money) Compare(other Money) int {
+	if c := strings.Compare(m.currency, other.currency); c != 0 {
+		return c
+	}
+	scale := max(m.scale, other.scale)
+	return m.rescaled(scale).Cmp(other.rescaled(scale))
+}
+
+// Equal reports whether m and other are the same amount in the same currency
+func (m Money) Equal(other Money) bool {
+	return m.Compare(other) == 0
+}
+
+// Float64 returns the nearest float64 to m, for display or statistics only
+func (m Money) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(m.int(), pow10(m.scale)).Float64()
+	return f
+}
+
+// String returns m with all its decimal places, followed by its currency
+// if it has one, e.g. "12.50 USD"
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.decimal()
+	}
+	return m.decimal() + " " + m.currency
+}
+
+// decimal formats the amount without currency
+func (m Money) decimal() string {
+	digits := new(big.Int).Abs(m.int()).String()
+	if m.scale > 0 {
+		if pad := int(m.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(m.scale)] + "." + digits[len(digits)-int(m.scale):]
+	}
+	if m.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON implements json.Marshaler
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.labeled && m.currency != "" {
+		return []byte(`"` + m.String() + `"`), nil
+	}
+	return []byte(m.decimal()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		s, _, err := unquote(data)
+		if err != nil {
+			return err
+		}
+		parsed, err := ParseMoney(s)
+		if err != nil {
+			return err
+		}
+		parsed.labeled = parsed.currency != ""
+		*m = parsed
+		return nil
+	}
+	parsed, err := parseDecimal(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+	*m = parsed
+	return nil
+}
+
+// SumMoney returns the exact sum of values
+func SumMoney(values ...Money) (Money, error) {
+	var sum Money
+	for _, value := range values {
+		var err error
+		if sum, err = sum.Add(value); err != nil {
+			return Money{}, err
+		}
+	}
+	return sum, nil
+}
+
+func (m Money) int() *big.Int {
+	if m.coef == nil {
+		return new(big.Int)
+	}
+	return m.coef
+}
+
+// rescaled returns the coefficient of m at a scale of at least m.scale
+func (m Money) rescaled(scale int32) *big.Int {
+	if scale == m.scale {
+		return m.int()
+	}
+	return new(big.Int).Mul(m.int(), pow10(scale-m.scale))
+}
+
+// commonCurrency returns the currency of the result of an operation on m
+// and other
+func (m Money) commonCurrency(other Money, op string) (string, error) {
+	switch {
+	case m.currency == "":
+		return other.currency, nil
+	case other.currency == "" || other.currency == m.currency:
+		return m.currency, nil
+	default:
+		return "", fmt.Errorf("cannot %s %s and %s amounts: %w", op, m.currency, other.currency, ErrCurrencyMismatch)
+	}
+}
+
+// unscaled returns coef*10^-scale at a scale of at least 0, as NewMoney
+// stores it
+func unscaled(coef *big.Int, scale int32) (*big.Int, int32) {
+	if scale >= 0 {
+		return coef, scale
+	}
+	return coef.Mul(coef, pow10(-scale)), 0
+}
+
+// pow10 returns 10^n for n >= 0
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundQuo returns num/den rounded to an integer with mode
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+	sign := num.Sign() * den.Sign()
+	twice := new(big.Int).Abs(rem)
+	half := twice.Lsh(twice, 1).Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = half >= 0
+	case RoundHalfEven:
+		away = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	case RoundHalfDown:
+		away = half > 0
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	}
+	if away {
+		quo.Add(quo, big.NewInt(int64(sign)))
+	}
+	return quo
+}
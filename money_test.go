@@ -0,0 +1,225 @@
+package goapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/classify-api/goapi"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     string
+		currency string
+		wantErr  bool
+	}{
+		{in: "12.50", want: "12.50"},
+		{in: "-3", want: "-3"},
+		{in: "+0.05", want: "0.05"},
+		{in: ".5", want: "0.5"},
+		{in: "1e-3", want: "0.001"},
+		{in: "1.2E3", want: "1200"},
+		{in: "12.50 USD", want: "12.50", currency: "USD"},
+		{in: "EUR 3", want: "3", currency: "EUR"},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "12 usd", wantErr: true},
+		{in: "12 USD EUR", wantErr: true},
+	}
+	for _, tt := range tests {
+		m, err := goapi.ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %v, want error", tt.in, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q): %v", tt.in, err)
+			continue
+		}
+		if got := m.WithCurrency("").String(); got != tt.want || m.Currency() != tt.currency {
+			t.Errorf("ParseMoney(%q) = %q %q, want %q %q", tt.in, got, m.Currency(), tt.want, tt.currency)
+		}
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	modes := []goapi.RoundingMode{
+		goapi.RoundHalfUp, goapi.RoundHalfEven, goapi.RoundHalfDown,
+		goapi.RoundUp, goapi.RoundDown, goapi.RoundCeiling, goapi.RoundFloor,
+	}
+	tests := []struct {
+		in   string
+		want []string // One per mode, in the order of modes
+	}{
+		{in: "2.345", want: []string{"2.35", "2.34", "2.34", "2.35", "2.34", "2.35", "2.34"}},
+		{in: "-2.345", want: []string{"-2.35", "-2.34", "-2.34", "-2.35", "-2.34", "-2.34", "-2.35"}},
+		{in: "2.335", want: []string{"2.34", "2.34", "2.33", "2.34", "2.33", "2.34", "2.33"}},
+		{in: "2.3451", want: []string{"2.35", "2.35", "2.35", "2.35", "2.34", "2.35", "2.34"}},
+		{in: "0.001", want: []string{"0.00", "0.00", "0.00", "0.01", "0.00", "0.01", "0.00"}},
+		{in: "1.5", want: []string{"1.50", "1.50", "1.50", "1.50", "1.50", "1.50", "1.50"}},
+	}
+	for _, tt := range tests {
+		for i, mode := range modes {
+			if got := goapi.MustParseMoney(tt.in).Round(2, mode).String(); got != tt.want[i] {
+				t.Errorf("%s rounded with mode %d = %s, want %s", tt.in, mode, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestMoneyRoundNegativeScale(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale int32
+		mode  goapi.RoundingMode
+		want  string
+	}{
+		{in: "123", scale: -1, mode: goapi.RoundHalfUp, want: "120"},
+		{in: "125", scale: -1, mode: goapi.RoundHalfUp, want: "130"},
+		{in: "125", scale: -1, mode: goapi.RoundHalfEven, want: "120"},
+		{in: "1234.56", scale: -2, mode: goapi.RoundHalfUp, want: "1200"},
+		{in: "-1250 USD", scale: -2, mode: goapi.RoundFloor, want: "-1300 USD"},
+		{in: "49", scale: -2, mode: goapi.RoundUp, want: "100"},
+	}
+	for _, tt := range tests {
+		if got := goapi.MustParseMoney(tt.in).Round(tt.scale, tt.mode).String(); got != tt.want {
+			t.Errorf("%s rounded to scale %d = %s, want %s", tt.in, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: `12.10`, want: `12.10`},
+		{in: `"12.10"`, want: `12.10`},
+		{in: `0.1000000000000000055511151231257827`, want: `0.1000000000000000055511151231257827`},
+		{in: `"7.005 USD"`, want: `"7.005 USD"`},
+		{in: `"7.005"`, want: `7.005`},
+		{in: `-1e2`, want: `-100`},
+		{in: `null`, want: `0`},
+	}
+	for _, tt := range tests {
+		var m goapi.Money
+		if err := json.Unmarshal([]byte(tt.in), &m); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		out, err := json.Marshal(m)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("round trip of %s = %s, %v, want %s", tt.in, out, err, tt.want)
+		}
+	}
+	for _, bad := range []string{`true`, `"abc"`, `{}`, `"1 usd"`} {
+		var m goapi.Money
+		if err := json.Unmarshal([]byte(bad), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want error", bad, m)
+		}
+	}
+}
+
+func TestMoneyJSONCurrency(t *testing.T) {
+	tests := []struct {
+		name string
+		in   goapi.Money
+		want string
+	}{
+		{name: "parsed", in: goapi.MustParseMoney("12.50 USD"), want: `12.50`},
+		{name: "attached", in: goapi.MustParseMoney("12.50").WithCurrency("USD"), want: `12.50`},
+		{name: "labeled", in: goapi.MustParseMoney("12.50 USD").Labeled(), want: `"12.50 USD"`},
+		{name: "labeled without currency", in: goapi.MustParseMoney("12.50").Labeled(), want: `12.50`},
+		{name: "sum with labeled", in: must(goapi.MustParseMoney("1 USD").Labeled().Add(goapi.MustParseMoney("2"))), want: `"3 USD"`},
+	}
+	for _, tt := range tests {
+		out, err := json.Marshal(tt.in)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("%s: Marshal = %s, %v, want %s", tt.name, out, err, tt.want)
+		}
+	}
+
+	// Requests carry plain numbers even when the amount has a currency
+	out, err := json.Marshal(goapi.ProfileReimbursement{Amount: goapi.MustParseMoney("12.50 USD")})
+	if err != nil || !strings.Contains(string(out), `"amount":12.50,`) {
+		t.Errorf("reimbursement = %s, %v, want a numeric amount", out, err)
+	}
+}
+
+func must(m goapi.Money, err error) goapi.Money {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	sum := goapi.Money{}
+	for i := 0; i < 10000; i++ {
+		var err error
+		if sum, err = sum.Add(goapi.MustParseMoney("0.10")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sum.String() != "1000.00" {
+		t.Errorf("sum = %s, want 1000.00", sum)
+	}
+
+	total, err := goapi.MustParseMoney("25.00 USD").Mul(goapi.MustParseMoney("7.5"))
+	if err != nil || total.String() != "187.500 USD" {
+		t.Errorf("Mul = %s, %v", total, err)
+	}
+	third, err := goapi.MustParseMoney("10").Div(goapi.MustParseMoney("3"), 4, goapi.RoundHalfEven)
+	if err != nil || third.String() != "3.3333" {
+		t.Errorf("Div = %s, %v", third, err)
+	}
+	for _, tt := range []struct {
+		scale int32
+		want  string
+	}{{-1, "330"}, {-2, "300"}, {-3, "0"}, {0, "333"}} {
+		got, err := goapi.MustParseMoney("1000").Div(goapi.MustParseMoney("3"), tt.scale, goapi.RoundHalfUp)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("1000/3 to scale %d = %s, %v, want %s", tt.scale, got, err, tt.want)
+		}
+	}
+	if _, err := goapi.MustParseMoney("10").Div(goapi.Money{}, 2, goapi.RoundHalfUp); !errors.Is(err, goapi.ErrDivisionByZero) {
+		t.Errorf("Div by zero: %v", err)
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd, eur := goapi.MustParseMoney("12.50 USD"), goapi.MustParseMoney("3 EUR")
+	if _, err := usd.Add(eur); !errors.Is(err, goapi.ErrCurrencyMismatch) {
+		t.Errorf("Add: %v", err)
+	}
+	if _, err := usd.Sub(eur); !errors.Is(err, goapi.ErrCurrencyMismatch) {
+		t.Errorf("Sub: %v", err)
+	}
+	if _, err := goapi.SumMoney(usd, goapi.MustParseMoney("1"), eur); !errors.Is(err, goapi.ErrCurrencyMismatch) {
+		t.Errorf("SumMoney: %v", err)
+	}
+
+	// Sorting mixed currencies never fails
+	amounts := []goapi.Money{usd, goapi.MustParseMoney("1"), eur, goapi.MustParseMoney("2.00 USD")}
+	slices.SortFunc(amounts, goapi.Money.Compare)
+	var got []string
+	for _, m := range amounts {
+		got = append(got, m.String())
+	}
+	want := []string{"1", "3 EUR", "2.00 USD", "12.50 USD"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted = %q, want %q", got, want)
+	}
+	if !goapi.MustParseMoney("12.5 USD").Equal(usd) {
+		t.Error("12.5 USD != 12.50 USD")
+	}
+}
@@ -0,0 +1,225 @@
+package goapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// NextCursorHeader carries the cursor of the next page when the server
+// answers a paged request with a bare JSON array
+const NextCursorHeader = "X-Next-Cursor"
+
+// ListOptions selects a page of a collection
+type ListOptions struct {
+	PageSize int    // Items per page, 0 for the server default
+	Cursor   string // Cursor of the page to fetch, empty for the first page
+}
+
+func (o ListOptions) values() url.Values {
+	query := url.Values{}
+	if o.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	return query
+}
+
+// Page is one page of a collection
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // Empty on the last page
+}
+
+// HasNext reports whether there is a page after this one
+func (p *Page[T]) HasNext() bool {
+	return p.NextCursor != ""
+}
+
+// pageEnvelope covers the paged response shapes the API returns besides a
+// bare array: {"data": [...], "next_cursor": "..."} and variants of it
+type pageEnvelope struct {
+	Data       json.RawMessage `json:"data"`
+	Items      json.RawMessage `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+	Meta       struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"meta"`
+}
+
+// getPage fetches one page of the collection at path
+func getPage[T any](ctx context.Context, c *Client, operation, path string, query url.Values, opts ListOptions) (*Page[T], error) {
+	for key, values := range opts.values() {
+		query[key] = values
+	}
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+
+	response, err := c.makeRequest(ctx, operation, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	header := response.Header
+	var raw json.RawMessage
+	if err := parseJSONResponse(ctx, response, &raw); err != nil {
+		return nil, err
+	}
+	return decodePage[T](raw, header)
+}
+
+func decodePage[T any](raw json.RawMessage, header http.Header) (*Page[T], error) {
+	page := &Page[T]{NextCursor: header.Get(NextCursorHeader)}
+
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var envelope pageEnvelope
+		if err := json.Unmarshal(trimmed, &envelope); err != nil {
+			return nil, fmt.Errorf("error decoding response: %w", err)
+		}
+		trimmed = envelope.Data
+		if len(trimmed) == 0 {
+			trimmed = envelope.Items
+		}
+		if envelope.NextCursor != "" {
+			page.NextCursor = envelope.NextCursor
+		} else if envelope.Meta.NextCursor != "" {
+			page.NextCursor = envelope.Meta.NextCursor
+		}
+	}
+	if len(trimmed) > 0 {
+		if err := json.Unmarshal(trimmed, &page.Items); err != nil {
+			return nil, fmt.Errorf("error decoding response: %w", err)
+		}
+	}
+	return page, nil
+}
+
+// Iterator walks a paged collection, fetching pages lazily:
+//
+//	it := client.ListTimeSheets(profileID, goapi.ListOptions{PageSize: 500})
+//	for it.Next(ctx) {
+//		sheet := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, opts ListOptions) (*Page[T], error)
+	opts  ListOptions
+
+	items   []T
+	index   int
+	current T
+	done    bool
+	err     error
+}
+
+func newIterator[T any](opts ListOptions, fetch func(ctx context.Context, opts ListOptions) (*Page[T], error)) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, opts: opts, index: -1}
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false at the end of the collection or on error.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for {
+		if it.err != nil {
+			return false
+		}
+		if it.index+1 < len(it.items) {
+			it.index++
+			it.current = it.items[it.index]
+			return true
+		}
+		if it.done {
+			return false
+		}
+
+		page, err := it.fetch(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.items, it.index = page.Items, -1
+		if !page.HasNext() || page.NextCursor == it.opts.Cursor {
+			it.done = true
+		}
+		it.opts.Cursor = page.NextCursor
+	}
+}
+
+// Value returns the item Next advanced to
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect reads the remaining items of the collection into a slice
+func (it *Iterator[T]) Collect(ctx context.Context) ([]T, error) {
+	var all []T
+	for it.Next(ctx) {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
+
+// GetUsersPage retrieves one page of users
+func (c *Client) GetUsersPage(ctx context.Context, opts ListOptions) (*Page[User], error) {
+	return getPage[User](ctx, c, "GetUsersPage", "/users", url.Values{}, opts)
+}
+
+// ListUsers returns an iterator over all users
+func (c *Client) ListUsers(opts ListOptions) *Iterator[User] {
+	return newIterator(opts, c.GetUsersPage)
+}
+
+// GetTimeSheetsPage retrieves one page of time sheets for a specific user
+// profile
+func (c *Client) GetTimeSheetsPage(ctx context.Context, userProfileID string, opts ListOptions) (*Page[ProfileTimeSheet], error) {
+	query := url.Values{"user_profile_id": {userProfileID}}
+	return getPage[ProfileTimeSheet](ctx, c, "GetTimeSheetsPage", "/time_sheets", query, opts)
+}
+
+// ListTimeSheets returns an iterator over the time sheets of a specific user
+// profile
+func (c *Client) ListTimeSheets(userProfileID string, opts ListOptions) *Iterator[ProfileTimeSheet] {
+	return newIterator(opts, func(ctx context.Context, opts ListOptions) (*Page[ProfileTimeSheet], error) {
+		return c.GetTimeSheetsPage(ctx, userProfileID, opts)
+	})
+}
+
+// GetReimbursementsPage retrieves one page of reimbursements for a specific
+// user profile
+func (c *Client) GetReimbursementsPage(ctx context.Context, userProfileID string, opts ListOptions) (*Page[ProfileReimbursement], error) {
+	query := url.Values{"user_profile_id": {userProfileID}}
+	return getPage[ProfileReimbursement](ctx, c, "GetReimbursementsPage", "/reimbursements", query, opts)
+}
+
+// ListReimbursements returns an iterator over the reimbursements of a
+// specific user profile
+func (c *Client) ListReimbursements(userProfileID string, opts ListOptions) *Iterator[ProfileReimbursement] {
+	return newIterator(opts, func(ctx context.Context, opts ListOptions) (*Page[ProfileReimbursement], error) {
+		return c.GetReimbursementsPage(ctx, userProfileID, opts)
+	})
+}
+
+// GetProductsPage retrieves one page of products
+func (c *Client) GetProductsPage(ctx context.Context, opts ListOptions) (*Page[Product], error) {
+	return getPage[Product](ctx, c, "GetProductsPage", "/products", url.Values{}, opts)
+}
+
+// ListProducts returns an iterator over all products
+func (c *Client) ListProducts(opts ListOptions) *Iterator[Product] {
+	return newIterator(opts, c.GetProductsPage)
+}
@@ -0,0 +1,68 @@
+package goapi_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+func TestListProductsFollowsCursors(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	var want []string
+	for _, name := range []string{"Yoga", "Pilates", "Spin", "Boxing", "Rowing"} {
+		product, err := client.CreateProduct(goapi.Product{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, product.ID)
+	}
+
+	products, err := client.ListProducts(goapi.ListOptions{PageSize: 2}).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, product := range products {
+		got = append(got, product.ID)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ids = %v, want %v", got, want)
+	}
+
+	var cursors []string
+	for _, req := range srv.RequestsTo(http.MethodGet, "/products") {
+		cursors = append(cursors, req.Query.Get("cursor"))
+	}
+	if !slices.Equal(cursors, []string{"", "2", "4"}) {
+		t.Errorf("cursors = %q, want [\"\" 2 4]", cursors)
+	}
+}
+
+func TestGetProductsPage(t *testing.T) {
+	_, client := goapitest.NewTestClient(t, "gym-north")
+	for _, name := range []string{"Yoga", "Pilates", "Spin"} {
+		if _, err := client.CreateProduct(goapi.Product{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	first, err := client.GetProductsPage(ctx, goapi.ListOptions{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 2 || !first.HasNext() {
+		t.Fatalf("first page: %d items, HasNext %v; want 2 items and more", len(first.Items), first.HasNext())
+	}
+	last, err := client.GetProductsPage(ctx, goapi.ListOptions{PageSize: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last.Items) != 1 || last.HasNext() {
+		t.Errorf("last page: %d items, HasNext %v; want 1 item and no more", len(last.Items), last.HasNext())
+	}
+}
@@ -1,11 +1,10 @@
 package goapi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
+	"time"
 )
 
 // Models
@@ -20,37 +19,46 @@ type ProductSchedule struct {
 	ID        string                   `json:"id"`
 	TenantID  string                   `json:"tenant_id"`
 	ProductID string                   `json:"product_id"`
-	BeginDate string                   `json:"begin_date"` // ISO format date
-	EndDate   string                   `json:"end_date"`   // ISO format date
-	TimeZone  string                   `json:"time_zone"`  // IANA Time Zone
+	BeginDate Date                     `json:"begin_date"`
+	EndDate   Date                     `json:"end_date"`
+	TimeZone  string                   `json:"time_zone"` // IANA Time Zone
 	Sessions  []ProductScheduleSession `json:"sessions"`
 }
 
+// Location loads the IANA time zone of the schedule
+func (s ProductSchedule) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading time zone of schedule %s: %w", s.ID, err)
+	}
+	return loc, nil
+}
+
 type ProductScheduleSession struct {
 	ID                string                           `json:"id"`
 	TenantID          string                           `json:"tenant_id"`
 	ProductScheduleID string                           `json:"product_schedule_id"`
 	LocationID        string                           `json:"location_id"`
-	Day               string                           `json:"day"`        // Day of the week
-	BeginTime         string                           `json:"begin_time"` // Format "HH:MM"
+	Day               Weekday                          `json:"day"`
+	BeginTime         *TimeOfDay                       `json:"begin_time"`
 	DurationMinutes   int                              `json:"duration_minutes"`
 	Staff             []ProductScheduleSessionUser     `json:"staff"`
 	Resources         []ProductScheduleSessionResource `json:"resources"`
 }
 
 type ProductScheduleSessionUser struct {
-	TenantID                 string `json:"tenant_id"`
-	ProductScheduleSessionID string `json:"product_schedule_session_id"`
-	UserID                   string `json:"user_id"`
-	Role                     string `json:"role"`
+	TenantID                 string    `json:"tenant_id"`
+	ProductScheduleSessionID string    `json:"product_schedule_session_id"`
+	UserID                   string    `json:"user_id"`
+	Role                     StaffRole `json:"role"`
 }
 
 type ProductScheduleSessionResource struct {
-	TenantID                 string `json:"tenant_id"`
-	ProductScheduleSessionID string `json:"product_schedule_session_id"`
-	ResourceID               string `json:"resource_id"`
-	BeginTime                string `json:"begin_time"` // Format "HH:MM"
-	DurationMinutes          int    `json:"duration_minutes"`
+	TenantID                 string     `json:"tenant_id"`
+	ProductScheduleSessionID string     `json:"product_schedule_session_id"`
+	ResourceID               string     `json:"resource_id"`
+	BeginTime                *TimeOfDay `json:"begin_time"`
+	DurationMinutes          int        `json:"duration_minutes"`
 }
 
 type ProductScheduleSessionInstance struct {
@@ -59,17 +67,17 @@ type ProductScheduleSessionInstance struct {
 	ProductID                string                                     `json:"product_id"`
 	ProductScheduleID        string                                     `json:"product_schedule_id"`
 	ProductScheduleSessionID string                                     `json:"product_schedule_session_id"`
-	Date                     string                                     `json:"date"`       // ISO format date
-	BeginTime                string                                     `json:"begin_time"` // Format "HH:MM"
+	Date                     Date                                       `json:"date"`
+	BeginTime                *TimeOfDay                                 `json:"begin_time"`
 	AttendanceRecords        []ProductScheduleSessionInstanceAttendance `json:"attendance_records"`
 	Trials                   []ProductScheduleSessionInstanceTrials     `json:"trials"`
 }
 
 type ProductScheduleSessionInstanceAttendance struct {
-	TenantID          string `json:"tenant_id"`
-	SessionInstanceID string `json:"session_instance_id"`
-	SubscriberID      string `json:"subscriber_id"`
-	Attendance        string `json:"attendance"`
+	TenantID          string     `json:"tenant_id"`
+	SessionInstanceID string     `json:"session_instance_id"`
+	SubscriberID      string     `json:"subscriber_id"`
+	Attendance        Attendance `json:"attendance"`
 }
 
 type ProductScheduleSessionInstanceTrials struct {
@@ -78,97 +86,109 @@ type ProductScheduleSessionInstanceTrials struct {
 	SubscriberID      string `json:"subscriber_id"`
 }
 
-// Helper function to parse JSON responses
-func parseJSONResponse(response *http.Response, result interface{}) error {
-	defer response.Body.Close()
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(body, result)
-}
-
-// Helper function to format query parameters
-func formatQueryParams(params map[string]string) string {
-	query := url.Values{}
-	for key, value := range params {
-		query.Add(key, value)
-	}
-	return query.Encode()
+// GetProducts retrieves a list of products
+func (c *Client) GetProducts() ([]Product, error) {
+	return c.GetProductsContext(context.Background())
 }
 
-// GetProducts retrieves a list of products
-func GetProducts() ([]Product, error) {
+// GetProductsContext is like GetProducts but honors the cancellation and deadline of ctx
+func (c *Client) GetProductsContext(ctx context.Context) ([]Product, error) {
 	var products []Product
-	response, err := makeRequest("GET", "/products", nil)
+	response, err := c.makeRequest(ctx, "GetProducts", "GET", "/products", nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &products); err != nil {
+	if err := parseJSONResponse(ctx, response, &products); err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
 // FilterProducts retrieves a list of products with the specified filters
-func FilterProducts(filters map[string]string) ([]Product, error) {
+func (c *Client) FilterProducts(filters map[string]string) ([]Product, error) {
+	return c.FilterProductsContext(context.Background(), filters)
+}
+
+// FilterProductsContext is like FilterProducts but honors the cancellation and deadline of ctx
+func (c *Client) FilterProductsContext(ctx context.Context, filters map[string]string) ([]Product, error) {
 	var products []Product
 	query := "?" + formatQueryParams(filters)
-	response, err := makeRequest("GET", "/products/filter"+query, nil)
+	response, err := c.makeRequest(ctx, "FilterProducts", "GET", "/products/filter"+query, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &products); err != nil {
+	if err := parseJSONResponse(ctx, response, &products); err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
 // CreateProduct creates a new product
-func CreateProduct(product Product) (*Product, error) {
+func (c *Client) CreateProduct(product Product) (*Product, error) {
+	return c.CreateProductContext(context.Background(), product)
+}
+
+// CreateProductContext is like CreateProduct but honors the cancellation and deadline of ctx
+func (c *Client) CreateProductContext(ctx context.Context, product Product) (*Product, error) {
 	var createdProduct Product
-	response, err := makeRequest("POST", "/products", product)
+	response, err := c.makeValidatedRequest(ctx, "CreateProduct", "POST", "/products", product)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdProduct); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdProduct); err != nil {
 		return nil, err
 	}
 	return &createdProduct, nil
 }
 
 // GetProduct retrieves a single product by ID
-func GetProduct(productID string) (*Product, error) {
+func (c *Client) GetProduct(productID string) (*Product, error) {
+	return c.GetProductContext(context.Background(), productID)
+}
+
+// GetProductContext is like GetProduct but honors the cancellation and deadline of ctx
+func (c *Client) GetProductContext(ctx context.Context, productID string) (*Product, error) {
 	var product Product
-	response, err := makeRequest("GET", "/products/"+productID, nil)
+	response, err := c.makeRequest(ctx, "GetProduct", "GET", "/products/"+productID, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &product); err != nil {
+	if err := parseJSONResponse(ctx, response, &product); err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
 
 // UpdateProduct updates an existing product
-func UpdateProduct(productID string, product Product) (*Product, error) {
+func (c *Client) UpdateProduct(productID string, product Product) (*Product, error) {
+	return c.UpdateProductContext(context.Background(), productID, product)
+}
+
+// UpdateProductContext is like UpdateProduct but honors the cancellation and deadline of ctx
+func (c *Client) UpdateProductContext(ctx context.Context, productID string, product Product) (*Product, error) {
 	var updatedProduct Product
-	response, err := makeRequest("PUT", "/products/"+productID, product)
+	response, err := c.makeValidatedRequest(ctx, "UpdateProduct", "PUT", "/products/"+productID, product)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &updatedProduct); err != nil {
+	if err := parseJSONResponse(ctx, response, &updatedProduct); err != nil {
 		return nil, err
 	}
 	return &updatedProduct, nil
 }
 
 // DeleteProduct deletes a product by ID
-func DeleteProduct(productID string) error {
-	response, err := makeRequest("DELETE", "/products/"+productID, nil)
+func (c *Client) DeleteProduct(productID string) error {
+	return c.DeleteProductContext(context.Background(), productID)
+}
+
+// DeleteProductContext is like DeleteProduct but honors the cancellation and deadline of ctx
+func (c *Client) DeleteProductContext(ctx context.Context, productID string) error {
+	response, err := c.makeRequest(ctx, "DeleteProduct", "DELETE", "/products/"+productID, nil)
 	if err != nil {
 		return err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("failed to delete product, status code: %d", response.StatusCode)
 	}
@@ -176,78 +196,108 @@ func DeleteProduct(productID string) error {
 }
 
 // CreateProductSchedule creates a new product schedule
-func CreateProductSchedule(schedule ProductSchedule) (*ProductSchedule, error) {
+func (c *Client) CreateProductSchedule(schedule ProductSchedule) (*ProductSchedule, error) {
+	return c.CreateProductScheduleContext(context.Background(), schedule)
+}
+
+// CreateProductScheduleContext is like CreateProductSchedule but honors the cancellation and deadline of ctx
+func (c *Client) CreateProductScheduleContext(ctx context.Context, schedule ProductSchedule) (*ProductSchedule, error) {
 	var createdSchedule ProductSchedule
-	response, err := makeRequest("POST", "/product_schedules", schedule)
+	response, err := c.makeValidatedRequest(ctx, "CreateProductSchedule", "POST", "/product_schedules", schedule)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdSchedule); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdSchedule); err != nil {
 		return nil, err
 	}
 	return &createdSchedule, nil
 }
 
 // GetProductSchedule retrieves a product schedule by ID
-func GetProductSchedule(scheduleID string) (*ProductSchedule, error) {
+func (c *Client) GetProductSchedule(scheduleID string) (*ProductSchedule, error) {
+	return c.GetProductScheduleContext(context.Background(), scheduleID)
+}
+
+// GetProductScheduleContext is like GetProductSchedule but honors the cancellation and deadline of ctx
+func (c *Client) GetProductScheduleContext(ctx context.Context, scheduleID string) (*ProductSchedule, error) {
 	var schedule ProductSchedule
-	response, err := makeRequest("GET", "/product_schedules/"+scheduleID, nil)
+	response, err := c.makeRequest(ctx, "GetProductSchedule", "GET", "/product_schedules/"+scheduleID, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &schedule); err != nil {
+	if err := parseJSONResponse(ctx, response, &schedule); err != nil {
 		return nil, err
 	}
 	return &schedule, nil
 }
 
 // CreateProductScheduleSession creates a new product schedule session
-func CreateProductScheduleSession(session ProductScheduleSession) (*ProductScheduleSession, error) {
+func (c *Client) CreateProductScheduleSession(session ProductScheduleSession) (*ProductScheduleSession, error) {
+	return c.CreateProductScheduleSessionContext(context.Background(), session)
+}
+
+// CreateProductScheduleSessionContext is like CreateProductScheduleSession but honors the cancellation and deadline of ctx
+func (c *Client) CreateProductScheduleSessionContext(ctx context.Context, session ProductScheduleSession) (*ProductScheduleSession, error) {
 	var createdSession ProductScheduleSession
-	response, err := makeRequest("POST", "/product_schedule_sessions", session)
+	response, err := c.makeValidatedRequest(ctx, "CreateProductScheduleSession", "POST", "/product_schedule_sessions", session)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdSession); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdSession); err != nil {
 		return nil, err
 	}
 	return &createdSession, nil
 }
 
 // GetProductScheduleSession retrieves a product schedule session by ID
-func GetProductScheduleSession(sessionID string) (*ProductScheduleSession, error) {
+func (c *Client) GetProductScheduleSession(sessionID string) (*ProductScheduleSession, error) {
+	return c.GetProductScheduleSessionContext(context.Background(), sessionID)
+}
+
+// GetProductScheduleSessionContext is like GetProductScheduleSession but honors the cancellation and deadline of ctx
+func (c *Client) GetProductScheduleSessionContext(ctx context.Context, sessionID string) (*ProductScheduleSession, error) {
 	var session ProductScheduleSession
-	response, err := makeRequest("GET", "/product_schedule_sessions/"+sessionID, nil)
+	response, err := c.makeRequest(ctx, "GetProductScheduleSession", "GET", "/product_schedule_sessions/"+sessionID, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &session); err != nil {
+	if err := parseJSONResponse(ctx, response, &session); err != nil {
 		return nil, err
 	}
 	return &session, nil
 }
 
 // CreateProductScheduleSessionUser creates a new product schedule session user
-func CreateProductScheduleSessionUser(sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error) {
+func (c *Client) CreateProductScheduleSessionUser(sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error) {
+	return c.CreateProductScheduleSessionUserContext(context.Background(), sessionUser)
+}
+
+// CreateProductScheduleSessionUserContext is like CreateProductScheduleSessionUser but honors the cancellation and deadline of ctx
+func (c *Client) CreateProductScheduleSessionUserContext(ctx context.Context, sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error) {
 	var createdSessionUser ProductScheduleSessionUser
-	response, err := makeRequest("POST", "/product_schedule_session_users", sessionUser)
+	response, err := c.makeValidatedRequest(ctx, "CreateProductScheduleSessionUser", "POST", "/product_schedule_session_users", sessionUser)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdSessionUser); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdSessionUser); err != nil {
 		return nil, err
 	}
 	return &createdSessionUser, nil
 }
 
 // CreateProductScheduleSessionResource creates a new product schedule session resource
-func CreateProductScheduleSessionResource(sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error) {
+func (c *Client) CreateProductScheduleSessionResource(sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error) {
+	return c.CreateProductScheduleSessionResourceContext(context.Background(), sessionResource)
+}
+
+// CreateProductScheduleSessionResourceContext is like CreateProductScheduleSessionResource but honors the cancellation and deadline of ctx
+func (c *Client) CreateProductScheduleSessionResourceContext(ctx context.Context, sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error) {
 	var createdSessionResource ProductScheduleSessionResource
-	response, err := makeRequest("POST", "/product_schedule_session_resources", sessionResource)
+	response, err := c.makeValidatedRequest(ctx, "CreateProductScheduleSessionResource", "POST", "/product_schedule_session_resources", sessionResource)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdSessionResource); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdSessionResource); err != nil {
 		return nil, err
 	}
 	return &createdSessionResource, nil
@@ -0,0 +1,285 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned without contacting the server while it has
+// asked the client to back off for longer than the limiter's maximum wait
+var ErrRateLimited = errors.New("rate limited by server")
+
+// defaultMaxRateLimitWait matches the default RetryPolicy.MaxRetryAfter
+const defaultMaxRateLimitWait = 30 * time.Second
+
+// RateLimiter is a token bucket that paces requests and adapts to the
+// X-RateLimit-* and Retry-After headers sent by the server
+type RateLimiter struct {
+	mu           sync.Mutex // Mutex for thread-safe access to the fields below
+	rate         float64    // Tokens added per second, <= 0 for no local limit
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	maxWait      time.Duration
+
+	serverLimit     int
+	serverRemaining int
+	serverReset     time.Time
+}
+
+// RateBudget is a snapshot of a RateLimiter that batch jobs can use to pace
+// themselves
+type RateBudget struct {
+	Available    float64   // Requests that can be sent right now without waiting
+	Rate         float64   // Configured requests per second, 0 for no local limit
+	Burst        int       // Configured bucket size
+	BlockedUntil time.Time // Set while the server asked the client to back off
+
+	// Quota reported by the server, -1 when it sent no X-RateLimit headers
+	ServerLimit     int
+	ServerRemaining int
+	ServerReset     time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate requests per second with
+// bursts of up to burst requests. A rate of 0 or less sets no local limit but
+// still honors the server's rate limit headers.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:            rate,
+		burst:           float64(burst),
+		tokens:          float64(burst),
+		last:            time.Now(),
+		maxWait:         defaultMaxRateLimitWait,
+		serverLimit:     -1,
+		serverRemaining: -1,
+	}
+}
+
+// SetMaxWait sets how long Wait may block on a back-off requested by the
+// server (30s by default). Longer back-offs fail with ErrRateLimited instead.
+func (l *RateLimiter) SetMaxWait(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxWait = d
+}
+
+// Wait blocks until a request may be sent or ctx is done. It fails fast with
+// ErrRateLimited while the server asks for a longer back-off than allowed by
+// SetMaxWait.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		var wait time.Duration
+		if now.Before(l.blockedUntil) {
+			wait = l.blockedUntil.Sub(now)
+			if wait > l.maxWait {
+				until := l.blockedUntil
+				l.mu.Unlock()
+				return fmt.Errorf("server asked to wait until %s: %w", until.Format(time.RFC3339), ErrRateLimited)
+			}
+		} else if l.rate <= 0 {
+			l.mu.Unlock()
+			return nil
+		} else {
+			l.refill(now)
+			if l.tokens >= 1 {
+				l.tokens--
+				l.mu.Unlock()
+				return nil
+			}
+			wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Budget returns the current state of the limiter
+func (l *RateLimiter) Budget() RateBudget {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	budget := RateBudget{
+		Burst:           int(l.burst),
+		ServerLimit:     l.serverLimit,
+		ServerRemaining: l.serverRemaining,
+		ServerReset:     l.serverReset,
+	}
+	if now.Before(l.blockedUntil) {
+		budget.BlockedUntil = l.blockedUntil
+	}
+	if l.rate > 0 {
+		l.refill(now)
+		budget.Rate = l.rate
+		budget.Available = l.tokens
+	} else {
+		budget.Available = l.burst
+	}
+	if !budget.BlockedUntil.IsZero() {
+		budget.Available = 0
+	}
+	return budget
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// observe adapts the limiter to the quota headers of a response
+func (l *RateLimiter) observe(resp *http.Response) {
+	now := time.Now()
+	limit, hasLimit := headerInt(resp.Header, "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(resp.Header, "X-RateLimit-Remaining")
+	reset, hasReset := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now)
+
+	var retryAfter time.Duration
+	hasRetryAfter := false
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter, hasRetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if hasLimit {
+		l.serverLimit = limit
+	}
+	if hasRemaining {
+		l.serverRemaining = remaining
+	}
+	if hasReset {
+		l.serverReset = reset
+	}
+	if hasRemaining && remaining <= 0 && hasReset && reset.After(l.blockedUntil) {
+		l.blockedUntil = reset
+	}
+	if hasRetryAfter && now.Add(retryAfter).After(l.blockedUntil) {
+		l.blockedUntil = now.Add(retryAfter)
+	}
+}
+
+func headerInt(header http.Header, key string) (int, bool) {
+	value, err := strconv.Atoi(header.Get(key))
+	return value, err == nil
+}
+
+// parseRateLimitReset accepts both seconds until the reset and a Unix
+// timestamp, telling them apart by magnitude
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+	if seconds > 1e9 {
+		return time.Unix(seconds, 0), true
+	}
+	return now.Add(time.Duration(seconds) * time.Second), true
+}
+
+// WithRateLimit limits the client to rate requests per second with bursts
+// of up to burst requests, across all tenants
+func WithRateLimit(rate float64, burst int) Option {
+	return WithRateLimiter(NewRateLimiter(rate, burst))
+}
+
+// WithRateLimiter makes the client wait on limiter before every request,
+// which lets several clients share one budget
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimits.client = limiter
+	}
+}
+
+// WithTenantRateLimit gives every tenant its own limit of rate requests per
+// second with bursts of up to burst requests, on top of any client limit
+func WithTenantRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimits.tenantRate = rate
+		c.rateLimits.tenantBurst = burst
+	}
+}
+
+// rateLimits holds the limiters of a client and its tenant views
+type rateLimits struct {
+	client      *RateLimiter
+	tenantRate  float64
+	tenantBurst int
+
+	mu      sync.Mutex // Mutex for thread-safe access to tenants
+	tenants map[string]*RateLimiter
+}
+
+func (r *rateLimits) tenantsEnabled() bool {
+	return r.tenantRate > 0 || r.tenantBurst > 0
+}
+
+func (r *rateLimits) forTenant(tenant string) *RateLimiter {
+	if !r.tenantsEnabled() {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tenants == nil {
+		r.tenants = make(map[string]*RateLimiter)
+	}
+	limiter, ok := r.tenants[tenant]
+	if !ok {
+		limiter = NewRateLimiter(r.tenantRate, r.tenantBurst)
+		r.tenants[tenant] = limiter
+	}
+	return limiter
+}
+
+// waitRateLimit blocks until both the client and the tenant limiter allow a
+// request
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if c.rateLimits.client != nil {
+		if err := c.rateLimits.client.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if limiter := c.rateLimits.forTenant(c.tenantName); limiter != nil {
+		return limiter.Wait(ctx)
+	}
+	return nil
+}
+
+// observeRateLimit feeds server quota headers to the most specific limiter,
+// since the server counts requests per token
+func (c *Client) observeRateLimit(resp *http.Response) {
+	if limiter := c.rateLimits.forTenant(c.tenantName); limiter != nil {
+		limiter.observe(resp)
+	} else if c.rateLimits.client != nil {
+		c.rateLimits.client.observe(resp)
+	}
+}
+
+// RateBudget returns the budget of the limiter governing the client's
+// tenant, and false if no rate limit is configured
+func (c *Client) RateBudget() (RateBudget, bool) {
+	if limiter := c.rateLimits.forTenant(c.tenantName); limiter != nil {
+		return limiter.Budget(), true
+	}
+	if c.rateLimits.client != nil {
+		return c.rateLimits.client.Budget(), true
+	}
+	return RateBudget{}, false
+}
@@ -0,0 +1,195 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	l := NewRateLimiter(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 2 {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("burst took %v, want no wait", elapsed)
+	}
+	if budget := l.Budget(); budget.Available >= 1 || budget.Rate != 50 || budget.Burst != 2 {
+		t.Errorf("budget after burst = %+v", budget)
+	}
+
+	// The third request waits for a token at 50 per second
+	if err := l.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("third request after %v, want about 20ms", elapsed)
+	}
+
+	// Waiting gives up with ctx
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want a deadline error", err)
+	}
+}
+
+func TestRateLimiterWithoutLocalLimit(t *testing.T) {
+	l := NewRateLimiter(0, 0)
+	for range 100 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if budget := l.Budget(); budget.Rate != 0 || budget.Available != 1 || budget.ServerLimit != -1 {
+		t.Errorf("budget = %+v", budget)
+	}
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		status      int
+		header      map[string]string
+		wantBlocked time.Duration // 0 for not blocked
+		wantLimit   int
+		wantLeft    int
+	}{
+		{
+			name:        "retry after on 429",
+			status:      http.StatusTooManyRequests,
+			header:      map[string]string{"Retry-After": "5"},
+			wantBlocked: 5 * time.Second, wantLimit: -1, wantLeft: -1,
+		},
+		{
+			name:        "retry after on 503",
+			status:      http.StatusServiceUnavailable,
+			header:      map[string]string{"Retry-After": "5"},
+			wantBlocked: 5 * time.Second, wantLimit: -1, wantLeft: -1,
+		},
+		{
+			name:      "retry after on 200",
+			status:    http.StatusOK,
+			header:    map[string]string{"Retry-After": "5"},
+			wantLimit: -1, wantLeft: -1,
+		},
+		{
+			name:      "quota left",
+			status:    http.StatusOK,
+			header:    map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "7", "X-RateLimit-Reset": "10"},
+			wantLimit: 100, wantLeft: 7,
+		},
+		{
+			name:        "quota exhausted, reset in seconds",
+			status:      http.StatusOK,
+			header:      map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "10"},
+			wantBlocked: 10 * time.Second, wantLimit: 100, wantLeft: 0,
+		},
+		{
+			name:        "quota exhausted, reset as Unix time",
+			status:      http.StatusOK,
+			header:      map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(20*time.Second).Unix(), 10)},
+			wantBlocked: 20 * time.Second, wantLimit: -1, wantLeft: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(10, 5)
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for key, value := range tt.header {
+				resp.Header.Set(key, value)
+			}
+			l.observe(resp)
+
+			budget := l.Budget()
+			if budget.ServerLimit != tt.wantLimit || budget.ServerRemaining != tt.wantLeft {
+				t.Errorf("server quota = %d/%d, want %d/%d", budget.ServerRemaining, budget.ServerLimit, tt.wantLeft, tt.wantLimit)
+			}
+			if tt.wantBlocked == 0 {
+				if !budget.BlockedUntil.IsZero() || budget.Available != 5 {
+					t.Errorf("budget = %+v, want unblocked", budget)
+				}
+				return
+			}
+			if blocked := time.Until(budget.BlockedUntil); blocked < tt.wantBlocked-2*time.Second || blocked > tt.wantBlocked {
+				t.Errorf("blocked for %v, want %v", blocked, tt.wantBlocked)
+			}
+			if budget.Available != 0 {
+				t.Errorf("available = %v while blocked, want 0", budget.Available)
+			}
+		})
+	}
+}
+
+func TestRateLimiterLongBackOffFailsFast(t *testing.T) {
+	l := NewRateLimiter(0, 0)
+	l.observe(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"3600"}},
+	})
+
+	start := time.Now()
+	if err := l.Wait(context.Background()); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Wait = %v, want ErrRateLimited", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Wait blocked for %v", elapsed)
+	}
+
+	// A back-off within the maximum wait blocks instead
+	l.SetMaxWait(2 * time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want a deadline error", err)
+	}
+}
+
+func TestClientRateBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	if _, ok := New(srv.URL).RateBudget(); ok {
+		t.Error("RateBudget reported a budget without a rate limit")
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "client", opts: []Option{WithRateLimit(10, 3)}},
+		{name: "tenant", opts: []Option{WithRateLimit(100, 100), WithTenantRateLimit(10, 3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := New(srv.URL, append(tt.opts, WithTenantName("gym-north"))...)
+			if _, err := client.GetProducts(); err != nil {
+				t.Fatal(err)
+			}
+			budget, ok := client.RateBudget()
+			if !ok {
+				t.Fatal("no budget")
+			}
+			if budget.Rate != 10 || budget.Burst != 3 || budget.Available >= 3 {
+				t.Errorf("budget = %+v, want the 10/s limiter with a token used", budget)
+			}
+			if budget.ServerLimit != 60 || budget.ServerRemaining != 59 {
+				t.Errorf("server quota = %d/%d, want 59/60", budget.ServerRemaining, budget.ServerLimit)
+			}
+		})
+	}
+}
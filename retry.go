@@ -0,0 +1,184 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry; each further retry
+	// multiplies it by Multiplier up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Jitter randomly shortens each delay by up to this fraction (0 to 1)
+	Jitter float64
+
+	// MaxRetryAfter caps how long a Retry-After header may make the client
+	// wait; responses asking for longer are returned instead of retried
+	MaxRetryAfter time.Duration
+
+	// RetryStatuses lists the status codes that are retried
+	RetryStatuses map[int]bool
+
+	// RetryNonIdempotent allows retrying POST and PATCH requests that carry
+	// no idempotency key
+	RetryNonIdempotent bool
+
+	// RetryKeyedMutations allows retrying POST and PATCH requests whose
+	// Idempotency-Key was generated by the client. Enable it only if the
+	// server deduplicates on that header; keys supplied with
+	// WithIdempotencyKey make a request retryable regardless.
+	RetryKeyedMutations bool
+
+	// OnRetry, if set, is called before every retry
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a retry that is about to happen
+type RetryEvent struct {
+	Method     string
+	Path       string
+	Attempt    int           // The attempt that just failed, starting at 1
+	StatusCode int           // Status of the failed attempt, 0 for transport errors
+	Err        error         // Transport error of the failed attempt, if any
+	Delay      time.Duration // How long the client waits before retrying
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured: up to
+// 3 attempts on 429, 502, 503, 504 and transport errors for GET, PUT and
+// DELETE requests, and for POST and PATCH requests given an idempotency key
+// with WithIdempotencyKey. Other POST and PATCH requests are not retried.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxRetryAfter:  30 * time.Second,
+		RetryStatuses: map[int]bool{
+			http.StatusTooManyRequests:    true,
+			http.StatusBadGateway:         true,
+			http.StatusServiceUnavailable: true,
+			http.StatusGatewayTimeout:     true,
+		},
+	}
+}
+
+// NoRetries returns a policy that never retries
+func NoRetries() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// WithRetryPolicy sets the retry policy of the client
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// keyedRetrySafe reports whether the idempotency key of a request makes it
+// safe to retry: the caller chose the key, or the policy trusts the server
+// to deduplicate generated ones
+func (p *RetryPolicy) keyedRetrySafe(ctx context.Context, header http.Header) bool {
+	if header.Get(IdempotencyKeyHeader) == "" {
+		return false
+	}
+	_, fromCaller := IdempotencyKeyFromContext(ctx)
+	return fromCaller || p.RetryKeyedMutations
+}
+
+// shouldRetry reports whether the outcome of an attempt is worth retrying
+func (p *RetryPolicy) shouldRetry(ctx context.Context, idempotent bool, attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if !p.RetryNonIdempotent && !idempotent {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return p.RetryStatuses[resp.StatusCode]
+}
+
+// delay returns how long to wait after the given failed attempt, and false
+// if the server asked for a longer wait than the policy allows
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	backoff := float64(p.InitialBackoff) * math.Pow(math.Max(p.Multiplier, 1), float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	d := time.Duration(backoff)
+
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxRetryAfter > 0 && retryAfter > p.MaxRetryAfter {
+				return 0, false
+			}
+			if retryAfter > d {
+				d = retryAfter
+			}
+		}
+	}
+	return d, true
+}
+
+// parseRetryAfter understands both the delay-seconds and HTTP-date forms
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		d := time.Until(at)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
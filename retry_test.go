@@ -0,0 +1,80 @@
+package goapi_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+// statuses returns the response statuses of requests
+func statuses(requests []goapitest.Request) []int {
+	codes := make([]int, len(requests))
+	for i, req := range requests {
+		codes[i] = req.Status
+	}
+	return codes
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var delays []time.Duration
+	policy := goapi.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.OnRetry = func(event goapi.RetryEvent) {
+		delays = append(delays, event.Delay)
+	}
+	srv, client := goapitest.NewTestClient(t, "gym-north", goapi.WithRetryPolicy(policy))
+	srv.InjectFault(&goapitest.Fault{
+		Method: http.MethodGet,
+		Path:   "/products",
+		Status: http.StatusServiceUnavailable,
+		Header: http.Header{"Retry-After": {"1"}},
+		Times:  1,
+	})
+
+	if _, err := client.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses(srv.RequestsTo(http.MethodGet, "/products")); !slices.Equal(got, []int{503, 200}) {
+		t.Errorf("statuses = %v, want [503 200]", got)
+	}
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("delays = %v, want [1s]", delays)
+	}
+}
+
+func TestRetryGivesUpOnLongRetryAfter(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	srv.InjectFault(&goapitest.Fault{
+		Path:   "/products",
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": {"3600"}},
+	})
+
+	_, err := client.GetProducts()
+	var apiErr *goapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("err = %v, want a 429 APIError", err)
+	}
+	if got := len(srv.RequestsTo(http.MethodGet, "/products")); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	srv, client := goapitest.NewTestClient(t, "gym-north")
+	srv.InjectFault(&goapitest.Fault{Path: "/products", Status: http.StatusBadGateway})
+
+	_, err := client.GetProducts()
+	var apiErr *goapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("err = %v, want a 502 APIError", err)
+	}
+	if got := len(srv.RequestsTo(http.MethodGet, "/products")); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
@@ -0,0 +1,141 @@
+package goapi
+
+import "context"
+
+// The interfaces below group the API operations by domain. Client implements
+// all of them, so code can depend on the narrowest one it needs and tests can
+// substitute a fake (see the goapitest package).
+
+// UserService covers users and their profiles
+type UserService interface {
+	// GetUsers retrieves a list of users
+	GetUsers() ([]User, error)
+	GetUsersContext(ctx context.Context) ([]User, error)
+
+	// GetUser retrieves a single user by ID
+	GetUser(userID string) (*User, error)
+	GetUserContext(ctx context.Context, userID string) (*User, error)
+
+	// CreateUser creates a new user
+	CreateUser(user User) (*User, error)
+	CreateUserContext(ctx context.Context, user User) (*User, error)
+
+	// UpdateUser updates an existing user
+	UpdateUser(userID string, user User) (*User, error)
+	UpdateUserContext(ctx context.Context, userID string, user User) (*User, error)
+
+	// DeleteUser deletes a user by ID
+	DeleteUser(userID string) error
+	DeleteUserContext(ctx context.Context, userID string) error
+}
+
+// TimeService covers time sheets and clocking in and out
+type TimeService interface {
+	// GetTimeSheets retrieves time sheets for a specific user profile
+	GetTimeSheets(userProfileID string) ([]ProfileTimeSheet, error)
+	GetTimeSheetsContext(ctx context.Context, userProfileID string) ([]ProfileTimeSheet, error)
+
+	// CreateTimeSheet creates a new time sheet
+	CreateTimeSheet(timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+	CreateTimeSheetContext(ctx context.Context, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+
+	// UpdateTimeSheet updates an existing time sheet
+	UpdateTimeSheet(timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+	UpdateTimeSheetContext(ctx context.Context, timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+
+	// DeleteTimeSheet deletes a time sheet by ID
+	DeleteTimeSheet(timeSheetID string) error
+	DeleteTimeSheetContext(ctx context.Context, timeSheetID string) error
+
+	// ClockIn records a clock-in for a specific user profile
+	ClockIn(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+	ClockInContext(ctx context.Context, userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+
+	// ClockOut records a clock-out for a specific user profile
+	ClockOut(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+	ClockOutContext(ctx context.Context, userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error)
+}
+
+// ReimbursementService covers reimbursements
+type ReimbursementService interface {
+	// GetReimbursements retrieves reimbursements for a specific user profile
+	GetReimbursements(userProfileID string) ([]ProfileReimbursement, error)
+	GetReimbursementsContext(ctx context.Context, userProfileID string) ([]ProfileReimbursement, error)
+
+	// CreateReimbursement creates a new reimbursement
+	CreateReimbursement(reimbursement ProfileReimbursement) (*ProfileReimbursement, error)
+	CreateReimbursementContext(ctx context.Context, reimbursement ProfileReimbursement) (*ProfileReimbursement, error)
+
+	// UpdateReimbursement updates an existing reimbursement
+	UpdateReimbursement(reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error)
+	UpdateReimbursementContext(ctx context.Context, reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error)
+
+	// DeleteReimbursement deletes a reimbursement by ID
+	DeleteReimbursement(reimbursementID string) error
+	DeleteReimbursementContext(ctx context.Context, reimbursementID string) error
+}
+
+// ProductService covers products
+type ProductService interface {
+	// GetProducts retrieves a list of products
+	GetProducts() ([]Product, error)
+	GetProductsContext(ctx context.Context) ([]Product, error)
+
+	// FilterProducts retrieves a list of products with the specified filters
+	FilterProducts(filters map[string]string) ([]Product, error)
+	FilterProductsContext(ctx context.Context, filters map[string]string) ([]Product, error)
+
+	// GetProduct retrieves a single product by ID
+	GetProduct(productID string) (*Product, error)
+	GetProductContext(ctx context.Context, productID string) (*Product, error)
+
+	// CreateProduct creates a new product
+	CreateProduct(product Product) (*Product, error)
+	CreateProductContext(ctx context.Context, product Product) (*Product, error)
+
+	// UpdateProduct updates an existing product
+	UpdateProduct(productID string, product Product) (*Product, error)
+	UpdateProductContext(ctx context.Context, productID string, product Product) (*Product, error)
+
+	// DeleteProduct deletes a product by ID
+	DeleteProduct(productID string) error
+	DeleteProductContext(ctx context.Context, productID string) error
+}
+
+// ScheduleService covers product schedules and their sessions
+type ScheduleService interface {
+	// CreateProductSchedule creates a new product schedule
+	CreateProductSchedule(schedule ProductSchedule) (*ProductSchedule, error)
+	CreateProductScheduleContext(ctx context.Context, schedule ProductSchedule) (*ProductSchedule, error)
+
+	// GetProductSchedule retrieves a product schedule by ID
+	GetProductSchedule(scheduleID string) (*ProductSchedule, error)
+	GetProductScheduleContext(ctx context.Context, scheduleID string) (*ProductSchedule, error)
+
+	// CreateProductScheduleSession creates a new product schedule session
+	CreateProductScheduleSession(session ProductScheduleSession) (*ProductScheduleSession, error)
+	CreateProductScheduleSessionContext(ctx context.Context, session ProductScheduleSession) (*ProductScheduleSession, error)
+
+	// GetProductScheduleSession retrieves a product schedule session by ID
+	GetProductScheduleSession(sessionID string) (*ProductScheduleSession, error)
+	GetProductScheduleSessionContext(ctx context.Context, sessionID string) (*ProductScheduleSession, error)
+
+	// CreateProductScheduleSessionUser creates a new product schedule session user
+	CreateProductScheduleSessionUser(sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error)
+	CreateProductScheduleSessionUserContext(ctx context.Context, sessionUser ProductScheduleSessionUser) (*ProductScheduleSessionUser, error)
+
+	// CreateProductScheduleSessionResource creates a new product schedule session resource
+	CreateProductScheduleSessionResource(sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error)
+	CreateProductScheduleSessionResourceContext(ctx context.Context, sessionResource ProductScheduleSessionResource) (*ProductScheduleSessionResource, error)
+}
+
+// API covers every operation of the Classify API
+type API interface {
+	UserService
+	TimeService
+	ReimbursementService
+	ProductService
+	ScheduleService
+}
+
+var _ API = (*Client)(nil)
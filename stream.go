@@ -0,0 +1,256 @@
+package goapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit
+// set with WithMaxResponseSize
+var ErrResponseTooLarge = errors.New("response body exceeds maximum size")
+
+// WithMaxResponseSize makes reading a response body fail with
+// ErrResponseTooLarge once it exceeds n bytes. It applies to every call,
+// streamed or not. The default is no limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseSize = n
+	}
+}
+
+// limitedBody fails reads past a maximum size instead of truncating silently
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// Read one byte past the limit to tell an exact fit from an overflow
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// limitBody applies the client's maximum response size to resp
+func (c *Client) limitBody(resp *http.Response) {
+	if c.maxResponseSize > 0 {
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: c.maxResponseSize}
+	}
+}
+
+// Stream decodes the elements of a JSON array response one at a time, so a
+// large collection is never held in memory as a whole. A Stream must be
+// closed.
+type Stream[T any] struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	current T
+	started bool
+	done    bool
+	err     error
+}
+
+// Next decodes the next element. It returns false at the end of the array,
+// on error, or once ctx is done.
+func (s *Stream[T]) Next(ctx context.Context) bool {
+	if s.done || s.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		s.err = err
+		return false
+	}
+	if !s.started {
+		s.started = true
+		found, err := seekArray(s.decoder)
+		if err != nil {
+			s.fail(ctx, err)
+			return false
+		}
+		if !found {
+			s.done = true
+			return false
+		}
+	}
+	if !s.decoder.More() {
+		s.done = true
+		return false
+	}
+
+	var value T
+	if err := s.decoder.Decode(&value); err != nil {
+		s.fail(ctx, err)
+		return false
+	}
+	s.current = value
+	return true
+}
+
+func (s *Stream[T]) fail(ctx context.Context, err error) {
+	switch {
+	case ctx.Err() != nil:
+		s.err = ctx.Err()
+	case errors.Is(err, ErrResponseTooLarge):
+		s.err = err
+	default:
+		s.err = fmt.Errorf("error decoding response: %w", err)
+	}
+}
+
+// Value returns the element Next decoded
+func (s *Stream[T]) Value() T {
+	return s.current
+}
+
+// Err returns the error that stopped the stream, if any
+func (s *Stream[T]) Err() error {
+	return s.err
+}
+
+// Close releases the response body
+func (s *Stream[T]) Close() error {
+	s.done = true
+	return s.body.Close()
+}
+
+// seekArray advances the decoder to just inside the collection array, which
+// is either the whole body or the "data" or "items" member of an envelope.
+// It returns false if the body holds no array.
+func seekArray(decoder *json.Decoder) (bool, error) {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	switch token {
+	case json.Delim('['):
+		return true, nil
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return false, err
+			}
+			if key == "data" || key == "items" {
+				token, err := decoder.Token()
+				if err != nil {
+					return false, err
+				}
+				if token == json.Delim('[') {
+					return true, nil
+				}
+				if token == nil {
+					continue
+				}
+				return false, fmt.Errorf("expected array in %q, got %v", key, token)
+			}
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("expected JSON array, got %v", token)
+}
+
+type streamingContextKey struct{}
+
+// withStreaming marks a call whose body is decoded as it arrives. Such calls
+// bypass everything that would buffer the body, like the response cache and
+// request coalescing.
+func withStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamingContextKey{}, true)
+}
+
+func isStreaming(ctx context.Context) bool {
+	streaming, _ := ctx.Value(streamingContextKey{}).(bool)
+	return streaming
+}
+
+// openStream sends a GET request and returns a Stream over its body
+func openStream[T any](ctx context.Context, c *Client, operation, path string) (*Stream[T], error) {
+	response, err := c.makeRequest(withStreaming(ctx), operation, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream[T]{body: response.Body, decoder: json.NewDecoder(response.Body)}, nil
+}
+
+// forEach streams the collection at path into fn, stopping at the first
+// error fn returns
+func forEach[T any](ctx context.Context, c *Client, operation, path string, fn func(T) error) error {
+	stream, err := openStream[T](ctx, c, operation, path)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	for stream.Next(ctx) {
+		if err := fn(stream.Value()); err != nil {
+			return err
+		}
+	}
+	return stream.Err()
+}
+
+// StreamUsers opens a stream over all users
+func (c *Client) StreamUsers(ctx context.Context) (*Stream[User], error) {
+	return openStream[User](ctx, c, "StreamUsers", "/users")
+}
+
+// ForEachUser calls fn for every user as it is decoded
+func (c *Client) ForEachUser(ctx context.Context, fn func(User) error) error {
+	return forEach(ctx, c, "StreamUsers", "/users", fn)
+}
+
+// StreamTimeSheets opens a stream over the time sheets of a specific user
+// profile
+func (c *Client) StreamTimeSheets(ctx context.Context, userProfileID string) (*Stream[ProfileTimeSheet], error) {
+	return openStream[ProfileTimeSheet](ctx, c, "StreamTimeSheets", "/time_sheets?user_profile_id="+url.QueryEscape(userProfileID))
+}
+
+// ForEachTimeSheet calls fn for every time sheet of a specific user profile
+// as it is decoded
+func (c *Client) ForEachTimeSheet(ctx context.Context, userProfileID string, fn func(ProfileTimeSheet) error) error {
+	return forEach(ctx, c, "StreamTimeSheets", "/time_sheets?user_profile_id="+url.QueryEscape(userProfileID), fn)
+}
+
+// StreamReimbursements opens a stream over the reimbursements of a specific
+// user profile
+func (c *Client) StreamReimbursements(ctx context.Context, userProfileID string) (*Stream[ProfileReimbursement], error) {
+	return openStream[ProfileReimbursement](ctx, c, "StreamReimbursements", "/reimbursements?user_profile_id="+url.QueryEscape(userProfileID))
+}
+
+// ForEachReimbursement calls fn for every reimbursement of a specific user
+// profile as it is decoded
+func (c *Client) ForEachReimbursement(ctx context.Context, userProfileID string, fn func(ProfileReimbursement) error) error {
+	return forEach(ctx, c, "StreamReimbursements", "/reimbursements?user_profile_id="+url.QueryEscape(userProfileID), fn)
+}
+
+// StreamProducts opens a stream over all products
+func (c *Client) StreamProducts(ctx context.Context) (*Stream[Product], error) {
+	return openStream[Product](ctx, c, "StreamProducts", "/products")
+}
+
+// ForEachProduct calls fn for every product as it is decoded
+func (c *Client) ForEachProduct(ctx context.Context, fn func(Product) error) error {
+	return forEach(ctx, c, "StreamProducts", "/products", fn)
+}
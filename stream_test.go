@@ -0,0 +1,169 @@
+package goapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSeekArray(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantFound bool
+		wantErr   bool
+		wantIDs   []string
+	}{
+		{name: "bare array", body: `[{"id": "p1"}, {"id": "p2"}]`, wantFound: true, wantIDs: []string{"p1", "p2"}},
+		{name: "empty array", body: `[]`, wantFound: true},
+		{name: "data envelope", body: `{"total": 2, "data": [{"id": "p1"}, {"id": "p2"}]}`, wantFound: true, wantIDs: []string{"p1", "p2"}},
+		{name: "items envelope after nested members", body: `{"meta": {"data": [1], "next": null}, "items": [{"id": "p1"}]}`, wantFound: true, wantIDs: []string{"p1"}},
+		{name: "null data before items", body: `{"data": null, "items": [{"id": "p1"}]}`, wantFound: true, wantIDs: []string{"p1"}},
+		{name: "null body", body: `null`},
+		{name: "empty body", body: ``},
+		{name: "envelope without array", body: `{"total": 0, "data": null}`},
+		{name: "data is not an array", body: `{"data": {"id": "p1"}}`, wantErr: true},
+		{name: "scalar body", body: `"p1"`, wantErr: true},
+		{name: "truncated envelope", body: `{"data"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tt.body))
+			found, err := seekArray(decoder)
+			if (err != nil) != tt.wantErr || found != tt.wantFound {
+				t.Fatalf("seekArray = %v, %v; want %v with error %v", found, err, tt.wantFound, tt.wantErr)
+			}
+			if !found {
+				return
+			}
+			var ids []string
+			for decoder.More() {
+				var product Product
+				if err := decoder.Decode(&product); err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, product.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("elements = %q, want %q", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		limit   int64
+		wantErr bool
+	}{
+		{name: "under the limit", body: "abc", limit: 4},
+		{name: "exact fit", body: "abcd", limit: 4},
+		{name: "one byte over", body: "abcde", limit: 4, wantErr: true},
+		{name: "far over", body: strings.Repeat("a", 4096), limit: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &limitedBody{ReadCloser: io.NopCloser(strings.NewReader(tt.body)), remaining: tt.limit}
+			got, err := io.ReadAll(body)
+			if !tt.wantErr {
+				if err != nil || string(got) != tt.body {
+					t.Errorf("ReadAll = %q, %v; want %q", got, err, tt.body)
+				}
+				return
+			}
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("ReadAll error = %v, want ErrResponseTooLarge", err)
+			}
+			if int64(len(got)) > tt.limit+1 {
+				t.Errorf("read %d bytes past a limit of %d", len(got), tt.limit)
+			}
+			if n, err := body.Read(make([]byte, 8)); n != 0 || !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("Read after overflow = %d, %v; want ErrResponseTooLarge", n, err)
+			}
+		})
+	}
+}
+
+// newProductServer serves body as the product collection
+func newProductServer(t *testing.T, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStreamMaxResponseSize(t *testing.T) {
+	body := `{"data": [{"id": "p1"}, {"id": "p2"}]}`
+	srv := newProductServer(t, body)
+	ctx := context.Background()
+
+	// The second limit cuts the body inside the last product
+	for _, limit := range []int64{int64(len(body)), int64(len(body)) - 5} {
+		stream, err := New(srv.URL, WithMaxResponseSize(limit)).StreamProducts(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for stream.Next(ctx) {
+			count++
+		}
+		stream.Close()
+
+		if limit == int64(len(body)) {
+			if stream.Err() != nil || count != 2 {
+				t.Errorf("exact fit: %d products, err %v; want 2 and no error", count, stream.Err())
+			}
+		} else if !errors.Is(stream.Err(), ErrResponseTooLarge) {
+			t.Errorf("overflow: err = %v, want ErrResponseTooLarge", stream.Err())
+		}
+	}
+}
+
+func TestForEachStopsEarly(t *testing.T) {
+	srv := newProductServer(t, `[{"id": "p1"}, {"id": "p2"}, {"id": "p3"}]`)
+	client := New(srv.URL)
+	errStop := errors.New("stop")
+
+	var seen []string
+	err := client.ForEachProduct(context.Background(), func(p Product) error {
+		seen = append(seen, p.ID)
+		if p.ID == "p2" {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || strings.Join(seen, ",") != "p1,p2" {
+		t.Errorf("ForEachProduct = %v after %q, want errStop after p1,p2", err, seen)
+	}
+
+	// Cancelling ctx stops before the next element is decoded
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seen = nil
+	err = client.ForEachProduct(ctx, func(p Product) error {
+		seen = append(seen, p.ID)
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || len(seen) != 1 {
+		t.Errorf("ForEachProduct = %v after %q, want context.Canceled after p1", err, seen)
+	}
+
+	// Running to the end returns no error
+	seen = nil
+	if err := client.ForEachProduct(context.Background(), func(p Product) error {
+		seen = append(seen, p.ID)
+		return nil
+	}); err != nil || len(seen) != 3 {
+		t.Errorf("ForEachProduct = %v after %q, want all 3 products", err, seen)
+	}
+}
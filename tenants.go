@@ -0,0 +1,77 @@
+package goapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// tokenStore holds the JWT tokens of every tenant the user belongs to. It is
+// shared by a Client and the tenant views derived from it with ForTenant.
+type tokenStore struct {
+	authMu sync.Mutex // Serializes logins and token refreshes
+	mu     sync.Mutex // Mutex for thread-safe access to tokens
+	tokens map[string]tenantToken
+}
+
+type tenantToken struct {
+	token  string
+	expiry time.Time // Zero when the token carries no exp claim
+}
+
+func newTokenStore() *tokenStore {
+	return &tokenStore{tokens: make(map[string]tenantToken)}
+}
+
+func (s *tokenStore) get(tenant string) tenantToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[tenant]
+}
+
+func (s *tokenStore) set(tenant, token string) {
+	s.setAll(map[string]string{tenant: token})
+}
+
+// setAll stores the tokens of a login or refresh response, keeping tokens of
+// tenants the response does not mention
+func (s *tokenStore) setAll(tokens map[string]string) {
+	parsed := make(map[string]tenantToken, len(tokens))
+	for tenant, token := range tokens {
+		expiry, _ := jwtExpiry(token)
+		parsed[tenant] = tenantToken{token: token, expiry: expiry}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for tenant, t := range parsed {
+		s.tokens[tenant] = t
+	}
+}
+
+func (s *tokenStore) tenants() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenants := make([]string, 0, len(s.tokens))
+	for tenant, t := range s.tokens {
+		if t.token != "" {
+			tenants = append(tenants, tenant)
+		}
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// Tenants returns the names of the tenants the client holds tokens for,
+// which after Login are all the tenants the user belongs to
+func (c *Client) Tenants() []string {
+	return c.tokens.tenants()
+}
+
+// ForTenant returns a view of the client scoped to another tenant. The view
+// shares the HTTP client, configuration and token cache with c, so a single
+// Login covers every tenant and no new connections are opened.
+func (c *Client) ForTenant(tenant string) *Client {
+	view := *c
+	view.tenantName = tenant
+	return &view
+}
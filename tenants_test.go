@@ -0,0 +1,81 @@
+package goapi_test
+
+import (
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+// countingTransport counts the requests sent through it
+type countingTransport struct {
+	next  http.RoundTripper
+	count atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count.Add(1)
+	return t.next.RoundTrip(req)
+}
+
+func TestForTenantSharesLogin(t *testing.T) {
+	srv := goapitest.NewServer()
+	defer srv.Close()
+	srv.AddAccount(goapitest.TestEmail, goapitest.TestPassword, "gym-north", "gym-south")
+	transport := &countingTransport{next: http.DefaultTransport}
+	north := goapi.New(srv.URL,
+		goapi.WithHTTPClient(&http.Client{Transport: transport}),
+		goapi.WithTenantName("gym-north"),
+		goapi.WithCredentials(goapi.StaticCredentials(goapitest.TestEmail, goapitest.TestPassword)),
+	)
+
+	if err := north.Login(goapitest.TestEmail, goapitest.TestPassword); err != nil {
+		t.Fatal(err)
+	}
+	if got := north.Tenants(); !slices.Equal(got, []string{"gym-north", "gym-south"}) {
+		t.Fatalf("tenants = %q, want both", got)
+	}
+
+	// The view sends the south token without logging in again
+	south := north.ForTenant("gym-south")
+	if _, err := south.CreateProduct(goapi.Product{Name: "Spin"}); err != nil {
+		t.Fatal(err)
+	}
+	northProducts, err := north.GetProducts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	southProducts, err := south.GetProducts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(northProducts) != 0 || len(southProducts) != 1 {
+		t.Errorf("products north %+v, south %+v; want the product in the south only", northProducts, southProducts)
+	}
+	northToken, _ := north.GetJWT()
+	southToken, _ := south.GetJWT()
+	if northToken == "" || northToken == southToken {
+		t.Errorf("tokens north %q, south %q; want two different tokens", northToken, southToken)
+	}
+	if logins := len(srv.RequestsTo(http.MethodPost, "/login")); logins != 1 {
+		t.Errorf("logins = %d, want 1", logins)
+	}
+
+	// A login made by the view renews the tokens of the client too
+	srv.ExpireTokens()
+	if _, err := south.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := north.GetProducts(); err != nil {
+		t.Fatal(err)
+	}
+	if logins := len(srv.RequestsTo(http.MethodPost, "/login")); logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+	if sent := int(transport.count.Load()); sent != len(srv.Requests()) {
+		t.Errorf("transport saw %d requests, server %d; want every request through the shared HTTP client", sent, len(srv.Requests()))
+	}
+}
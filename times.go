@@ -0,0 +1,286 @@
+package goapi
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Date is a calendar date, "YYYY-MM-DD" on the wire. The zero Date is
+// encoded as null.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in t's location
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a "YYYY-MM-DD" date
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return DateOf(t), nil
+}
+
+// String returns the date as "YYYY-MM-DD", or "" for the zero Date
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether d is the zero Date
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns midnight at the start of d in loc
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDays returns the date n days after d
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.In(time.UTC).AddDate(0, 0, n))
+}
+
+// Weekday returns the day of the week of d
+func (d Date) Weekday() time.Weekday {
+	return d.In(time.UTC).Weekday()
+}
+
+// Compare returns -1, 0 or +1 as d is before, equal to or after other
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return compareInts(d.Year, other.Year)
+	case d.Month != other.Month:
+		return compareInts(int(d.Month), int(other.Month))
+	default:
+		return compareInts(d.Day, other.Day)
+	}
+}
+
+// Before reports whether d is before other
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// After reports whether d is after other
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, ok, err := unquote(data)
+	if !ok || err != nil {
+		return err
+	}
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// TimeOfDay is a wall-clock time without a date, "HH:MM" on the wire. Its
+// zero value is midnight, so fields that may be unset are *TimeOfDay and
+// encode nil as null.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+// TimeOfDayOf returns the wall-clock time of t in t's location
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute()}
+}
+
+// ParseTimeOfDay parses an "HH:MM" time of day
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	if len(s) != 5 || s[2] != ':' {
+		return TimeOfDay{}, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// String returns the time as "HH:MM"
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+// IsValid reports whether t is a time between 00:00 and 23:59
+func (t TimeOfDay) IsValid() bool {
+	return t.Hour >= 0 && t.Hour < 24 && t.Minute >= 0 && t.Minute < 60
+}
+
+// Minutes returns the number of minutes since midnight
+func (t TimeOfDay) Minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+// On returns the instant at which the wall clock shows t on date d in loc
+func (t TimeOfDay) On(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, 0, 0, loc)
+}
+
+// Compare returns -1, 0 or +1 as t is before, equal to or after other
+func (t TimeOfDay) Compare(other TimeOfDay) int {
+	return compareInts(t.Minutes(), other.Minutes())
+}
+
+// Before reports whether t is before other
+func (t TimeOfDay) Before(other TimeOfDay) bool {
+	return t.Compare(other) < 0
+}
+
+// After reports whether t is after other
+func (t TimeOfDay) After(other TimeOfDay) bool {
+	return t.Compare(other) > 0
+}
+
+// MarshalJSON implements json.Marshaler
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	if !t.IsValid() {
+		return nil, fmt.Errorf("invalid time of day %02d:%02d", t.Hour, t.Minute)
+	}
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	s, ok, err := unquote(data)
+	if !ok || err != nil {
+		return err
+	}
+	parsed, err := ParseTimeOfDay(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Timestamp is an instant, RFC 3339 on the wire. The zero Timestamp is
+// encoded as null.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns the Timestamp of t
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// ParseTimestamp parses an RFC 3339 timestamp
+func ParseTimestamp(s string) (Timestamp, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("invalid timestamp %q, expected RFC 3339", s)
+	}
+	return Timestamp{Time: t}, nil
+}
+
+// String returns the timestamp in RFC 3339 format, or "" for the zero
+// Timestamp
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// DateIn returns the date of t in loc
+func (t Timestamp) DateIn(loc *time.Location) Date {
+	return DateOf(t.In(loc))
+}
+
+// Compare returns -1, 0 or +1 as t is before, equal to or after other
+func (t Timestamp) Compare(other Timestamp) int {
+	return t.Time.Compare(other.Time)
+}
+
+// Before reports whether t is before other
+func (t Timestamp) Before(other Timestamp) bool {
+	return t.Time.Before(other.Time)
+}
+
+// After reports whether t is after other
+func (t Timestamp) After(other Timestamp) bool {
+	return t.Time.After(other.Time)
+}
+
+// MarshalJSON implements json.Marshaler
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s, ok, err := unquote(data)
+	if !ok || err != nil {
+		return err
+	}
+	if s == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// unquote returns the string in a JSON value. ok is false for null, which
+// leaves the destination unchanged.
+func unquote(data []byte) (s string, ok bool, err error) {
+	if bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' || bytes.ContainsAny(data[1:len(data)-1], `"\`) {
+		return "", false, fmt.Errorf("expected a JSON string, got %s", data)
+	}
+	return string(data[1 : len(data)-1]), true, nil
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,92 @@
+package goapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/classify-api/goapi"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    goapi.TimeOfDay
+		wantErr bool
+	}{
+		{in: "09:30", want: goapi.TimeOfDay{Hour: 9, Minute: 30}},
+		{in: "00:00", want: goapi.TimeOfDay{}},
+		{in: "23:59", want: goapi.TimeOfDay{Hour: 23, Minute: 59}},
+		{in: "9:30", wantErr: true},
+		{in: "09:5", wantErr: true},
+		{in: "24:00", wantErr: true},
+		{in: "12:60", wantErr: true},
+		{in: "09:30:00", wantErr: true},
+		{in: "0930", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := goapi.ParseTimeOfDay(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTimeOfDay(%q) = %v, %v, want %v (error %t)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestUnsetTimesEncodeAsNull(t *testing.T) {
+	out, err := json.Marshal(goapi.ProductScheduleSession{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"begin_time":null`) {
+		t.Errorf("unset begin_time encoded as %s", out)
+	}
+
+	out, err = json.Marshal(goapi.ProductSchedule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"begin_date":null`) {
+		t.Errorf("unset begin_date encoded as %s", out)
+	}
+
+	var session goapi.ProductScheduleSession
+	if err := json.Unmarshal([]byte(`{"begin_time":"00:00"}`), &session); err != nil {
+		t.Fatal(err)
+	}
+	if session.BeginTime == nil || *session.BeginTime != (goapi.TimeOfDay{}) {
+		t.Errorf("midnight decoded as %v", session.BeginTime)
+	}
+	if err := json.Unmarshal([]byte(`{"begin_time":"7:00"}`), &session); err == nil {
+		t.Error("7:00 accepted")
+	}
+}
+
+func TestDateAndTimestampJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: `{"begin_date":"2024-02-29","end_date":null}`},
+		{in: `{"begin_date":"2023-02-29"}`, wantErr: true},
+		{in: `{"begin_date":"2024-2-1"}`, wantErr: true},
+		{in: `{"begin_date":20240101}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var s goapi.ProductSchedule
+		if err := json.Unmarshal([]byte(tt.in), &s); (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s): %v, want error %t", tt.in, err, tt.wantErr)
+		}
+	}
+
+	var sheet goapi.ProfileTimeSheet
+	if err := json.Unmarshal([]byte(`{"time_in":"2024-03-01T09:00:00.5+01:00","time_out":null}`), &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if sheet.TimeOut != nil || sheet.TimeIn.UTC().Hour() != 8 {
+		t.Errorf("decoded %+v", sheet)
+	}
+	if err := json.Unmarshal([]byte(`{"time_in":"2024-03-01 09:00"}`), &sheet); err == nil {
+		t.Error("non-RFC 3339 timestamp accepted")
+	}
+}
@@ -0,0 +1,252 @@
+package goapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceParentHeader is the W3C Trace Context header injected into requests
+const TraceParentHeader = "traceparent"
+
+// Tracer starts a span for every API call. It is a small interface so that
+// any tracing library can be adapted without goapi depending on it.
+type Tracer interface {
+	Start(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is a single traced API call
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	SetStatus(status SpanStatus, description string)
+	End()
+}
+
+// SpanStatus is the outcome of a span
+type SpanStatus int
+
+const (
+	SpanStatusUnset SpanStatus = iota
+	SpanStatusOK
+	SpanStatusError
+)
+
+// SpanContext identifies a span across process boundaries
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// TraceParent formats the span context as a W3C traceparent header value
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]), flags)
+}
+
+// ParseTraceParent parses a W3C traceparent header value, e.g. one received
+// by a service that then calls the Classify API
+func ParseTraceParent(value string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return sc, fmt.Errorf("invalid traceparent %q", value)
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, fmt.Errorf("invalid traceparent %q", value)
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != 16 {
+		return sc, fmt.Errorf("invalid trace id in traceparent %q", value)
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != 8 {
+		return sc, fmt.Errorf("invalid span id in traceparent %q", value)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return sc, fmt.Errorf("invalid flags in traceparent %q", value)
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&1 == 1
+	if !sc.IsValid() {
+		return sc, fmt.Errorf("invalid traceparent %q", value)
+	}
+	return sc, nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of ctx carrying sc, which becomes the
+// parent of spans started by the built-in tracer and is propagated in the
+// traceparent header
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context carried by ctx
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// WithTracer makes the client start a span for every API call
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// startSpan starts the span of an API call and records its span context in
+// the returned ctx so that every attempt propagates it
+func (c *Client) startSpan(ctx context.Context, operation, method, path string) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := c.tracer.Start(ctx, operation)
+	span.SetAttribute("http.request.method", method)
+	urlPath, _, _ := strings.Cut(path, "?")
+	span.SetAttribute("url.path", urlPath)
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = ContextWithSpanContext(ctx, sc)
+	}
+	return ctx, span
+}
+
+// endSpan records the outcome of an API call and ends its span
+func endSpan(span Span, resp *http.Response, retries int, err error) {
+	if span == nil {
+		return
+	}
+	var apiErr *APIError
+	switch {
+	case resp != nil:
+		span.SetAttribute("http.response.status_code", resp.StatusCode)
+	case errors.As(err, &apiErr):
+		span.SetAttribute("http.response.status_code", apiErr.StatusCode)
+	}
+	span.SetAttribute("goapi.retries", retries)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(SpanStatusError, err.Error())
+	} else {
+		span.SetStatus(SpanStatusOK, "")
+	}
+	span.End()
+}
+
+// injectTraceParent propagates the span context of ctx, if any
+func injectTraceParent(ctx context.Context, req *http.Request) {
+	if sc, ok := SpanContextFromContext(ctx); ok {
+		req.Header.Set(TraceParentHeader, sc.TraceParent())
+	}
+}
+
+// SpanData is a finished span of the built-in tracer
+type SpanData struct {
+	Name              string
+	SpanContext       SpanContext
+	Parent            SpanContext // Zero for root spans
+	Start             time.Time
+	End               time.Time
+	Attributes        map[string]interface{}
+	Errors            []error
+	Status            SpanStatus
+	StatusDescription string
+}
+
+// NewTracer returns a dependency-free Tracer that continues the trace found
+// in the context, or starts a new sampled one, and hands every finished span
+// to onEnd
+func NewTracer(onEnd func(SpanData)) Tracer {
+	return &basicTracer{onEnd: onEnd}
+}
+
+type basicTracer struct {
+	onEnd func(SpanData)
+}
+
+func (t *basicTracer) Start(ctx context.Context, operation string) (context.Context, Span) {
+	span := &basicSpan{
+		tracer: t,
+		data: SpanData{
+			Name:       operation,
+			Start:      time.Now(),
+			Attributes: make(map[string]interface{}),
+		},
+	}
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		span.data.Parent = parent
+		span.data.SpanContext.TraceID = parent.TraceID
+		span.data.SpanContext.Sampled = parent.Sampled
+	} else {
+		rand.Read(span.data.SpanContext.TraceID[:])
+		span.data.SpanContext.Sampled = true
+	}
+	rand.Read(span.data.SpanContext.SpanID[:])
+	return ContextWithSpanContext(ctx, span.data.SpanContext), span
+}
+
+type basicSpan struct {
+	tracer *basicTracer
+
+	mu    sync.Mutex // Mutex for thread-safe access to data
+	data  SpanData
+	ended bool
+}
+
+func (s *basicSpan) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+func (s *basicSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes[key] = value
+}
+
+func (s *basicSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Errors = append(s.data.Errors, err)
+}
+
+func (s *basicSpan) SetStatus(status SpanStatus, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status = status
+	s.data.StatusDescription = description
+}
+
+func (s *basicSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.tracer.onEnd != nil {
+		s.tracer.onEnd(data)
+	}
+}
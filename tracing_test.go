@@ -0,0 +1,154 @@
+package goapi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/classify-api/goapi"
+	"github.com/classify-api/goapi/goapitest"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantErr     bool
+		wantSampled bool
+	}{
+		{name: "sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantSampled: true},
+		{name: "not sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+		{name: "future version with extra fields", value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-03-extra", wantSampled: true},
+		{name: "surrounding whitespace", value: " 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01\n", wantSampled: true},
+		{name: "extra fields in version 00", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{name: "forbidden version", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "short trace id", value: "00-4bf92f3577b34da6-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "bad flags", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := goapi.ParseTraceParent(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTraceParent = %+v, want an error", sc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.Sampled != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", sc.Sampled, tt.wantSampled)
+			}
+			// Formatting and parsing again gives back the same span context
+			again, err := goapi.ParseTraceParent(sc.TraceParent())
+			if err != nil || again != sc {
+				t.Errorf("round trip of %q = %+v, %v; want %+v", sc.TraceParent(), again, err, sc)
+			}
+		})
+	}
+
+	const value = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if sc, _ := goapi.ParseTraceParent(value); sc.TraceParent() != value {
+		t.Errorf("TraceParent() = %q, want %q", sc.TraceParent(), value)
+	}
+}
+
+// spanRecorder collects the spans finished by the built-in tracer
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []goapi.SpanData
+}
+
+func (r *spanRecorder) record(span goapi.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) named(name string) []goapi.SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var spans []goapi.SpanData
+	for _, span := range r.spans {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTracerPropagatesTraceParent(t *testing.T) {
+	parent, err := goapi.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		parent goapi.SpanContext // Zero for a root span
+	}{
+		{name: "root", ctx: context.Background()},
+		{name: "child", ctx: goapi.ContextWithSpanContext(context.Background(), parent), parent: parent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &spanRecorder{}
+			srv, client := goapitest.NewTestClient(t, "gym-north", goapi.WithTracer(goapi.NewTracer(recorder.record)))
+			if _, err := client.GetProductsContext(tt.ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			spans := recorder.named("GetProducts")
+			if len(spans) != 1 {
+				t.Fatalf("GetProducts spans = %d, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Parent != tt.parent {
+				t.Errorf("parent = %+v, want %+v", span.Parent, tt.parent)
+			}
+			switch {
+			case !span.SpanContext.IsValid():
+				t.Errorf("span context %+v is not valid", span.SpanContext)
+			case tt.parent.IsValid() && (span.SpanContext.TraceID != parent.TraceID || span.SpanContext.Sampled != parent.Sampled):
+				t.Errorf("span context = %+v, want to continue trace %+v", span.SpanContext, parent)
+			case tt.parent.IsValid() && span.SpanContext.SpanID == parent.SpanID:
+				t.Error("child span reuses the span id of its parent")
+			case !tt.parent.IsValid() && !span.SpanContext.Sampled:
+				t.Error("root span is not sampled")
+			}
+			if span.Status != goapi.SpanStatusOK || span.Attributes["http.response.status_code"] != 200 || span.Attributes["url.path"] != "/products" {
+				t.Errorf("span = %+v", span)
+			}
+
+			requests := srv.RequestsTo("GET", "/products")
+			if len(requests) != 1 {
+				t.Fatalf("requests = %d, want 1", len(requests))
+			}
+			if got, want := requests[0].Header.Get(goapi.TraceParentHeader), span.SpanContext.TraceParent(); got != want {
+				t.Errorf("traceparent = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTracerRecordsErrors(t *testing.T) {
+	recorder := &spanRecorder{}
+	srv, client := goapitest.NewTestClient(t, "gym-north", goapi.WithTracer(goapi.NewTracer(recorder.record)))
+	srv.InjectFault(&goapitest.Fault{Path: "/products", Status: 404})
+
+	if _, err := client.GetProducts(); !errors.Is(err, goapi.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	spans := recorder.named("GetProducts")
+	if len(spans) != 1 {
+		t.Fatalf("GetProducts spans = %d, want 1", len(spans))
+	}
+	if span := spans[0]; span.Status != goapi.SpanStatusError || len(span.Errors) != 1 || span.Attributes["http.response.status_code"] != 404 {
+		t.Errorf("span = %+v, want a 404 error", span)
+	}
+}
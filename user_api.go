@@ -1,6 +1,7 @@
 package goapi
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -34,109 +35,130 @@ type UserProfile struct {
 type Profiles []UserProfile
 
 type ProfilePayType struct {
-	ID            string  `json:"id"`
-	TenantID      string  `json:"tenant_id"`
-	UserProfileID string  `json:"user_profile_id"`
-	Name          string  `json:"name"`
-	PayRate       float64 `json:"pay_rate"` // Use float64 for decimals
-	EndDate       *string `json:"end_date"`
+	ID            string `json:"id"`
+	TenantID      string `json:"tenant_id"`
+	UserProfileID string `json:"user_profile_id"`
+	Name          string `json:"name"`
+	PayRate       Money  `json:"pay_rate"`
+	EndDate       *Date  `json:"end_date"`
 }
 
 type ProfileTimeSheet struct {
-	ID                string  `json:"id"`
-	TenantID          string  `json:"tenant_id"`
-	UserProfileID     string  `json:"user_profile_id"`
-	UserName          string  `json:"user_name"`
-	UserEmail         string  `json:"user_email"`
-	PayType           string  `json:"pay_type"`
-	PayRate           float64 `json:"pay_rate"`
-	TimeIn            string  `json:"time_in"` // Use string to match time format
-	ActualTimeIn      *string `json:"actual_time_in"`
-	OrigTimeIn        *string `json:"orig_time_in"`
-	LngIn             string  `json:"lng_in"`
-	LatIn             string  `json:"lat_in"`
-	ImageInURL        string  `json:"image_in_url"`
-	TimeOut           *string `json:"time_out"`
-	ActualTimeOut     *string `json:"actual_time_out"`
-	OrigTimeOut       *string `json:"orig_time_out"`
-	LngOut            string  `json:"lng_out"`
-	LatOut            string  `json:"lat_out"`
-	ImageOutURL       string  `json:"image_out_url"`
-	Total             float64 `json:"total"`
-	Note              string  `json:"note"`
-	Exceptions        string  `json:"exceptions"`
-	ExceptionsHandled bool    `json:"exceptions_handled"`
-	ManuallyEntered   bool    `json:"manually_entered"`
-	PayrollBatchID    *string `json:"payroll_batch_id"`
+	ID                string     `json:"id"`
+	TenantID          string     `json:"tenant_id"`
+	UserProfileID     string     `json:"user_profile_id"`
+	UserName          string     `json:"user_name"`
+	UserEmail         string     `json:"user_email"`
+	PayType           string     `json:"pay_type"`
+	PayRate           Money      `json:"pay_rate"`
+	TimeIn            Timestamp  `json:"time_in"`
+	ActualTimeIn      *Timestamp `json:"actual_time_in"`
+	OrigTimeIn        *Timestamp `json:"orig_time_in"`
+	LngIn             string     `json:"lng_in"`
+	LatIn             string     `json:"lat_in"`
+	ImageInURL        string     `json:"image_in_url"`
+	TimeOut           *Timestamp `json:"time_out"`
+	ActualTimeOut     *Timestamp `json:"actual_time_out"`
+	OrigTimeOut       *Timestamp `json:"orig_time_out"`
+	LngOut            string     `json:"lng_out"`
+	LatOut            string     `json:"lat_out"`
+	ImageOutURL       string     `json:"image_out_url"`
+	Total             Money      `json:"total"`
+	Note              string     `json:"note"`
+	Exceptions        string     `json:"exceptions"`
+	ExceptionsHandled bool       `json:"exceptions_handled"`
+	ManuallyEntered   bool       `json:"manually_entered"`
+	PayrollBatchID    *string    `json:"payroll_batch_id"`
 }
 
 type ProfileReimbursement struct {
-	ID             string  `json:"id"`
-	TenantID       string  `json:"tenant_id"`
-	UserProfileID  string  `json:"user_profile_id"`
-	UserName       string  `json:"user_name"`
-	UserEmail      string  `json:"user_email"`
-	Date           string  `json:"date"` // Use string to match date format
-	Amount         float64 `json:"amount"`
-	Reason         string  `json:"reason"`
-	ReceiptURL     string  `json:"receipt_url"`
-	Status         string  `json:"status"`
-	ApprovedAmount float64 `json:"approved_amount"`
-	Note           string  `json:"note"`
-	PayrollBatchID *string `json:"payroll_batch_id"`
+	ID             string              `json:"id"`
+	TenantID       string              `json:"tenant_id"`
+	UserProfileID  string              `json:"user_profile_id"`
+	UserName       string              `json:"user_name"`
+	UserEmail      string              `json:"user_email"`
+	Date           Date                `json:"date"`
+	Amount         Money               `json:"amount"`
+	Reason         string              `json:"reason"`
+	ReceiptURL     string              `json:"receipt_url"`
+	Status         ReimbursementStatus `json:"status"`
+	ApprovedAmount Money               `json:"approved_amount"`
+	Note           string              `json:"note"`
+	PayrollBatchID *string             `json:"payroll_batch_id"`
 }
 
 type DateFields struct {
-	CreatedAt *string `json:"created_at"`
-	UpdatedAt *string `json:"updated_at"`
-	DeletedAt *string `json:"deleted_at"`
+	CreatedAt *Timestamp `json:"created_at"`
+	UpdatedAt *Timestamp `json:"updated_at"`
+	DeletedAt *Timestamp `json:"deleted_at"`
 }
 
 // GetUsers retrieves a list of users
-func GetUsers() ([]User, error) {
+func (c *Client) GetUsers() ([]User, error) {
+	return c.GetUsersContext(context.Background())
+}
+
+// GetUsersContext is like GetUsers but honors the cancellation and deadline of ctx
+func (c *Client) GetUsersContext(ctx context.Context) ([]User, error) {
 	var users []User
-	response, err := makeRequest("GET", "/users", nil)
+	response, err := c.makeRequest(ctx, "GetUsers", "GET", "/users", nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &users); err != nil {
+	if err := parseJSONResponse(ctx, response, &users); err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
 // GetUser retrieves a single user by ID
-func GetUser(userID string) (*User, error) {
+func (c *Client) GetUser(userID string) (*User, error) {
+	return c.GetUserContext(context.Background(), userID)
+}
+
+// GetUserContext is like GetUser but honors the cancellation and deadline of ctx
+func (c *Client) GetUserContext(ctx context.Context, userID string) (*User, error) {
 	var user User
-	response, err := makeRequest("GET", "/users/"+userID, nil)
+	response, err := c.makeRequest(ctx, "GetUser", "GET", "/users/"+userID, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &user); err != nil {
+	if err := parseJSONResponse(ctx, response, &user); err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // CreateUser creates a new user
-func CreateUser(user User) (*User, error) {
+func (c *Client) CreateUser(user User) (*User, error) {
+	return c.CreateUserContext(context.Background(), user)
+}
+
+// CreateUserContext is like CreateUser but honors the cancellation and deadline of ctx
+func (c *Client) CreateUserContext(ctx context.Context, user User) (*User, error) {
 	var createdUser User
-	response, err := makeRequest("POST", "/users", user)
+	response, err := c.makeValidatedRequest(ctx, "CreateUser", "POST", "/users", user)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdUser); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdUser); err != nil {
 		return nil, err
 	}
 	return &createdUser, nil
 }
 
 // DeleteUser deletes a user by ID
-func DeleteUser(userID string) error {
-	response, err := makeRequest("DELETE", "/users/"+userID, nil)
+func (c *Client) DeleteUser(userID string) error {
+	return c.DeleteUserContext(context.Background(), userID)
+}
+
+// DeleteUserContext is like DeleteUser but honors the cancellation and deadline of ctx
+func (c *Client) DeleteUserContext(ctx context.Context, userID string) error {
+	response, err := c.makeRequest(ctx, "DeleteUser", "DELETE", "/users/"+userID, nil)
 	if err != nil {
 		return err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("failed to delete user, status code: %d", response.StatusCode)
 	}
@@ -144,63 +166,89 @@ func DeleteUser(userID string) error {
 }
 
 // UpdateUser updates an existing user
-func UpdateUser(userID string, user User) (*User, error) {
+func (c *Client) UpdateUser(userID string, user User) (*User, error) {
+	return c.UpdateUserContext(context.Background(), userID, user)
+}
+
+// UpdateUserContext is like UpdateUser but honors the cancellation and deadline of ctx
+func (c *Client) UpdateUserContext(ctx context.Context, userID string, user User) (*User, error) {
 	var updatedUser User
-	response, err := makeRequest("PUT", "/users/"+userID, user)
+	response, err := c.makeValidatedRequest(ctx, "UpdateUser", "PUT", "/users/"+userID, user)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &updatedUser); err != nil {
+	if err := parseJSONResponse(ctx, response, &updatedUser); err != nil {
 		return nil, err
 	}
 	return &updatedUser, nil
 }
 
 // GetTimeSheets retrieves time sheets for a specific user profile
-func GetTimeSheets(userProfileID string) ([]ProfileTimeSheet, error) {
+func (c *Client) GetTimeSheets(userProfileID string) ([]ProfileTimeSheet, error) {
+	return c.GetTimeSheetsContext(context.Background(), userProfileID)
+}
+
+// GetTimeSheetsContext is like GetTimeSheets but honors the cancellation and deadline of ctx
+func (c *Client) GetTimeSheetsContext(ctx context.Context, userProfileID string) ([]ProfileTimeSheet, error) {
 	var timeSheets []ProfileTimeSheet
-	response, err := makeRequest("GET", "/time_sheets?user_profile_id="+userProfileID, nil)
+	response, err := c.makeRequest(ctx, "GetTimeSheets", "GET", "/time_sheets?user_profile_id="+userProfileID, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &timeSheets); err != nil {
+	if err := parseJSONResponse(ctx, response, &timeSheets); err != nil {
 		return nil, err
 	}
 	return timeSheets, nil
 }
 
 // CreateTimeSheet creates a new time sheet
-func CreateTimeSheet(timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+func (c *Client) CreateTimeSheet(timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return c.CreateTimeSheetContext(context.Background(), timeSheet)
+}
+
+// CreateTimeSheetContext is like CreateTimeSheet but honors the cancellation and deadline of ctx
+func (c *Client) CreateTimeSheetContext(ctx context.Context, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
 	var createdTimeSheet ProfileTimeSheet
-	response, err := makeRequest("POST", "/time_sheets", timeSheet)
+	response, err := c.makeValidatedRequest(ctx, "CreateTimeSheet", "POST", "/time_sheets", timeSheet)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdTimeSheet); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdTimeSheet); err != nil {
 		return nil, err
 	}
 	return &createdTimeSheet, nil
 }
 
 // UpdateTimeSheet updates an existing time sheet
-func UpdateTimeSheet(timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+func (c *Client) UpdateTimeSheet(timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return c.UpdateTimeSheetContext(context.Background(), timeSheetID, timeSheet)
+}
+
+// UpdateTimeSheetContext is like UpdateTimeSheet but honors the cancellation and deadline of ctx
+func (c *Client) UpdateTimeSheetContext(ctx context.Context, timeSheetID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
 	var updatedTimeSheet ProfileTimeSheet
-	response, err := makeRequest("PUT", "/time_sheets/"+timeSheetID, timeSheet)
+	response, err := c.makeValidatedRequest(ctx, "UpdateTimeSheet", "PUT", "/time_sheets/"+timeSheetID, timeSheet)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &updatedTimeSheet); err != nil {
+	if err := parseJSONResponse(ctx, response, &updatedTimeSheet); err != nil {
 		return nil, err
 	}
 	return &updatedTimeSheet, nil
 }
 
 // DeleteTimeSheet deletes a time sheet by ID
-func DeleteTimeSheet(timeSheetID string) error {
-	response, err := makeRequest("DELETE", "/time_sheets/"+timeSheetID, nil)
+func (c *Client) DeleteTimeSheet(timeSheetID string) error {
+	return c.DeleteTimeSheetContext(context.Background(), timeSheetID)
+}
+
+// DeleteTimeSheetContext is like DeleteTimeSheet but honors the cancellation and deadline of ctx
+func (c *Client) DeleteTimeSheetContext(ctx context.Context, timeSheetID string) error {
+	response, err := c.makeRequest(ctx, "DeleteTimeSheet", "DELETE", "/time_sheets/"+timeSheetID, nil)
 	if err != nil {
 		return err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("failed to delete time sheet, status code: %d", response.StatusCode)
 	}
@@ -208,50 +256,71 @@ func DeleteTimeSheet(timeSheetID string) error {
 }
 
 // GetReimbursements retrieves reimbursements for a specific user profile
-func GetReimbursements(userProfileID string) ([]ProfileReimbursement, error) {
+func (c *Client) GetReimbursements(userProfileID string) ([]ProfileReimbursement, error) {
+	return c.GetReimbursementsContext(context.Background(), userProfileID)
+}
+
+// GetReimbursementsContext is like GetReimbursements but honors the cancellation and deadline of ctx
+func (c *Client) GetReimbursementsContext(ctx context.Context, userProfileID string) ([]ProfileReimbursement, error) {
 	var reimbursements []ProfileReimbursement
-	response, err := makeRequest("GET", "/reimbursements?user_profile_id="+userProfileID, nil)
+	response, err := c.makeRequest(ctx, "GetReimbursements", "GET", "/reimbursements?user_profile_id="+userProfileID, nil)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &reimbursements); err != nil {
+	if err := parseJSONResponse(ctx, response, &reimbursements); err != nil {
 		return nil, err
 	}
 	return reimbursements, nil
 }
 
 // CreateReimbursement creates a new reimbursement
-func CreateReimbursement(reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+func (c *Client) CreateReimbursement(reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+	return c.CreateReimbursementContext(context.Background(), reimbursement)
+}
+
+// CreateReimbursementContext is like CreateReimbursement but honors the cancellation and deadline of ctx
+func (c *Client) CreateReimbursementContext(ctx context.Context, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
 	var createdReimbursement ProfileReimbursement
-	response, err := makeRequest("POST", "/reimbursements", reimbursement)
+	response, err := c.makeValidatedRequest(ctx, "CreateReimbursement", "POST", "/reimbursements", reimbursement)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &createdReimbursement); err != nil {
+	if err := parseJSONResponse(ctx, response, &createdReimbursement); err != nil {
 		return nil, err
 	}
 	return &createdReimbursement, nil
 }
 
 // UpdateReimbursement updates an existing reimbursement
-func UpdateReimbursement(reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+func (c *Client) UpdateReimbursement(reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
+	return c.UpdateReimbursementContext(context.Background(), reimbursementID, reimbursement)
+}
+
+// UpdateReimbursementContext is like UpdateReimbursement but honors the cancellation and deadline of ctx
+func (c *Client) UpdateReimbursementContext(ctx context.Context, reimbursementID string, reimbursement ProfileReimbursement) (*ProfileReimbursement, error) {
 	var updatedReimbursement ProfileReimbursement
-	response, err := makeRequest("PUT", "/reimbursements/"+reimbursementID, reimbursement)
+	response, err := c.makeValidatedRequest(ctx, "UpdateReimbursement", "PUT", "/reimbursements/"+reimbursementID, reimbursement)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &updatedReimbursement); err != nil {
+	if err := parseJSONResponse(ctx, response, &updatedReimbursement); err != nil {
 		return nil, err
 	}
 	return &updatedReimbursement, nil
 }
 
 // DeleteReimbursement deletes a reimbursement by ID
-func DeleteReimbursement(reimbursementID string) error {
-	response, err := makeRequest("DELETE", "/reimbursements/"+reimbursementID, nil)
+func (c *Client) DeleteReimbursement(reimbursementID string) error {
+	return c.DeleteReimbursementContext(context.Background(), reimbursementID)
+}
+
+// DeleteReimbursementContext is like DeleteReimbursement but honors the cancellation and deadline of ctx
+func (c *Client) DeleteReimbursementContext(ctx context.Context, reimbursementID string) error {
+	response, err := c.makeRequest(ctx, "DeleteReimbursement", "DELETE", "/reimbursements/"+reimbursementID, nil)
 	if err != nil {
 		return err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("failed to delete reimbursement, status code: %d", response.StatusCode)
 	}
@@ -259,26 +328,36 @@ func DeleteReimbursement(reimbursementID string) error {
 }
 
 // ClockIn records a clock-in for a specific user profile
-func ClockIn(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+func (c *Client) ClockIn(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return c.ClockInContext(context.Background(), userProfileID, timeSheet)
+}
+
+// ClockInContext is like ClockIn but honors the cancellation and deadline of ctx
+func (c *Client) ClockInContext(ctx context.Context, userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
 	var clockedInTimeSheet ProfileTimeSheet
-	response, err := makeRequest("POST", "/clock_in?user_profile_id="+userProfileID, timeSheet)
+	response, err := c.makeRequest(ctx, "ClockIn", "POST", "/clock_in?user_profile_id="+userProfileID, timeSheet)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &clockedInTimeSheet); err != nil {
+	if err := parseJSONResponse(ctx, response, &clockedInTimeSheet); err != nil {
 		return nil, err
 	}
 	return &clockedInTimeSheet, nil
 }
 
 // ClockOut records a clock-out for a specific user profile
-func ClockOut(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+func (c *Client) ClockOut(userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
+	return c.ClockOutContext(context.Background(), userProfileID, timeSheet)
+}
+
+// ClockOutContext is like ClockOut but honors the cancellation and deadline of ctx
+func (c *Client) ClockOutContext(ctx context.Context, userProfileID string, timeSheet ProfileTimeSheet) (*ProfileTimeSheet, error) {
 	var clockedOutTimeSheet ProfileTimeSheet
-	response, err := makeRequest("POST", "/clock_out?user_profile_id="+userProfileID, timeSheet)
+	response, err := c.makeRequest(ctx, "ClockOut", "POST", "/clock_out?user_profile_id="+userProfileID, timeSheet)
 	if err != nil {
 		return nil, err
 	}
-	if err := parseJSONResponse(response, &clockedOutTimeSheet); err != nil {
+	if err := parseJSONResponse(ctx, response, &clockedOutTimeSheet); err != nil {
 		return nil, err
 	}
 	return &clockedOutTimeSheet, nil
@@ -0,0 +1,244 @@
+package goapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// ValidationError lists the problems Validate found in a model. It matches
+// ErrValidation through errors.Is, like a 422 from the server.
+type ValidationError struct {
+	Model       string
+	FieldErrors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.FieldErrors))
+	for i, fe := range e.FieldErrors {
+		messages[i] = fe.Error()
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Model, strings.Join(messages, "; "))
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// WithValidation makes every create and update call validate its model and
+// return the *ValidationError instead of sending an invalid request
+func WithValidation() Option {
+	return func(c *Client) {
+		c.validate = true
+	}
+}
+
+// validator is implemented by the models
+type validator interface {
+	Validate() error
+}
+
+// makeValidatedRequest is makeRequest for create and update calls: when
+// WithValidation is set, body is validated before anything is sent
+func (c *Client) makeValidatedRequest(ctx context.Context, operation, method, path string, body validator) (*http.Response, error) {
+	if c.validate {
+		if err := body.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return c.makeRequest(ctx, operation, method, path, body)
+}
+
+// Validate checks the email and the pay types of the profiles
+func (u User) Validate() error {
+	var errs fieldErrors
+	errs.required("email", u.Email == "")
+	errs.email("email", u.Email)
+	for i, profile := range u.Profiles {
+		profile.validate(&errs, fmt.Sprintf("profiles[%d].", i))
+	}
+	return errs.err("User")
+}
+
+// Validate checks the pay types of the profile
+func (p UserProfile) Validate() error {
+	var errs fieldErrors
+	p.validate(&errs, "")
+	return errs.err("UserProfile")
+}
+
+func (p UserProfile) validate(errs *fieldErrors, prefix string) {
+	for i, payType := range p.PayTypes {
+		payType.validate(errs, fmt.Sprintf("%spay_types[%d].", prefix, i))
+	}
+}
+
+// Validate checks the name and pay rate
+func (p ProfilePayType) Validate() error {
+	var errs fieldErrors
+	p.validate(&errs, "")
+	return errs.err("ProfilePayType")
+}
+
+func (p ProfilePayType) validate(errs *fieldErrors, prefix string) {
+	errs.required(prefix+"name", p.Name == "")
+	errs.nonNegative(prefix+"pay_rate", p.PayRate)
+}
+
+// Validate checks the profile, times and amounts of the time sheet
+func (t ProfileTimeSheet) Validate() error {
+	var errs fieldErrors
+	errs.required("user_profile_id", t.UserProfileID == "")
+	errs.required("time_in", t.TimeIn.IsZero())
+	if t.TimeOut != nil && !t.TimeIn.IsZero() && !t.TimeOut.After(t.TimeIn) {
+		errs.add("time_out", "invalid", "must be after time_in")
+	}
+	errs.nonNegative("pay_rate", t.PayRate)
+	errs.nonNegative("total", t.Total)
+	errs.email("user_email", t.UserEmail)
+	return errs.err("ProfileTimeSheet")
+}
+
+// Validate checks the profile, date, amounts and status of the
+// reimbursement
+func (r ProfileReimbursement) Validate() error {
+	var errs fieldErrors
+	errs.required("user_profile_id", r.UserProfileID == "")
+	errs.required("date", r.Date.IsZero())
+	errs.nonNegative("amount", r.Amount)
+	errs.nonNegative("approved_amount", r.ApprovedAmount)
+	if r.Status != "" && !r.Status.IsValid() {
+		errs.add("status", "invalid", fmt.Sprintf("unknown status %q", r.Status))
+	}
+	errs.email("user_email", r.UserEmail)
+	return errs.err("ProfileReimbursement")
+}
+
+// Validate checks the name of the product
+func (p Product) Validate() error {
+	var errs fieldErrors
+	errs.required("name", p.Name == "")
+	return errs.err("Product")
+}
+
+// Validate checks the product, dates and time zone of the schedule and its
+// sessions
+func (s ProductSchedule) Validate() error {
+	var errs fieldErrors
+	errs.required("product_id", s.ProductID == "")
+	errs.required("begin_date", s.BeginDate.IsZero())
+	if !s.EndDate.IsZero() && !s.EndDate.After(s.BeginDate) {
+		errs.add("end_date", "invalid", "must be after begin_date")
+	}
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			errs.add("time_zone", "invalid", fmt.Sprintf("unknown IANA time zone %q", s.TimeZone))
+		}
+	}
+	for i, session := range s.Sessions {
+		session.validate(&errs, fmt.Sprintf("sessions[%d].", i))
+	}
+	return errs.err("ProductSchedule")
+}
+
+// Validate checks the schedule, day, time and duration of the session and
+// its staff and resources
+func (s ProductScheduleSession) Validate() error {
+	var errs fieldErrors
+	errs.required("product_schedule_id", s.ProductScheduleID == "")
+	s.validate(&errs, "")
+	return errs.err("ProductScheduleSession")
+}
+
+func (s ProductScheduleSession) validate(errs *fieldErrors, prefix string) {
+	errs.required(prefix+"day", s.Day == "")
+	if s.Day != "" && !s.Day.IsValid() {
+		errs.add(prefix+"day", "invalid", fmt.Sprintf("unknown weekday %q", s.Day))
+	}
+	errs.timeOfDay(prefix+"begin_time", s.BeginTime)
+	errs.positive(prefix+"duration_minutes", s.DurationMinutes)
+	for i, staff := range s.Staff {
+		staff.validate(errs, fmt.Sprintf("%sstaff[%d].", prefix, i))
+	}
+	for i, resource := range s.Resources {
+		resource.validate(errs, fmt.Sprintf("%sresources[%d].", prefix, i))
+	}
+}
+
+// Validate checks the session and user of the assignment
+func (u ProductScheduleSessionUser) Validate() error {
+	var errs fieldErrors
+	errs.required("product_schedule_session_id", u.ProductScheduleSessionID == "")
+	u.validate(&errs, "")
+	return errs.err("ProductScheduleSessionUser")
+}
+
+func (u ProductScheduleSessionUser) validate(errs *fieldErrors, prefix string) {
+	errs.required(prefix+"user_id", u.UserID == "")
+}
+
+// Validate checks the session, resource, time and duration of the booking
+func (r ProductScheduleSessionResource) Validate() error {
+	var errs fieldErrors
+	errs.required("product_schedule_session_id", r.ProductScheduleSessionID == "")
+	r.validate(&errs, "")
+	return errs.err("ProductScheduleSessionResource")
+}
+
+func (r ProductScheduleSessionResource) validate(errs *fieldErrors, prefix string) {
+	errs.required(prefix+"resource_id", r.ResourceID == "")
+	errs.timeOfDay(prefix+"begin_time", r.BeginTime)
+	errs.positive(prefix+"duration_minutes", r.DurationMinutes)
+}
+
+// fieldErrors collects the problems found by Validate
+type fieldErrors []FieldError
+
+func (e *fieldErrors) add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+func (e *fieldErrors) required(field string, missing bool) {
+	if missing {
+		e.add(field, "required", "is required")
+	}
+}
+
+func (e *fieldErrors) email(field, address string) {
+	if address == "" {
+		return
+	}
+	if parsed, err := mail.ParseAddress(address); err != nil || parsed.Address != address {
+		e.add(field, "invalid", fmt.Sprintf("%q is not a valid email address", address))
+	}
+}
+
+func (e *fieldErrors) nonNegative(field string, amount Money) {
+	if amount.Sign() < 0 {
+		e.add(field, "invalid", "must not be negative")
+	}
+}
+
+func (e *fieldErrors) positive(field string, n int) {
+	if n <= 0 {
+		e.add(field, "invalid", "must be greater than 0")
+	}
+}
+
+func (e *fieldErrors) timeOfDay(field string, t *TimeOfDay) {
+	e.required(field, t == nil)
+	if t != nil && !t.IsValid() {
+		e.add(field, "invalid", "must be a time between 00:00 and 23:59")
+	}
+}
+
+func (e fieldErrors) err(model string) error {
+	if len(e) == 0 {
+		return nil
+	}
+	return &ValidationError{Model: model, FieldErrors: e}
+}
@@ -0,0 +1,138 @@
+package goapi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/classify-api/goapi"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	earlier := goapi.NewTimestamp(now.Add(-time.Hour))
+	nine := &goapi.TimeOfDay{Hour: 9}
+	tests := []struct {
+		name  string
+		model interface{ Validate() error }
+		want  []string // Fields reported, in order
+	}{
+		{name: "valid user", model: goapi.User{Email: "coach@example.com"}},
+		{name: "user without email", model: goapi.User{}, want: []string{"email"}},
+		{name: "user with display name", model: goapi.User{Email: "Coach <coach@example.com>"}, want: []string{"email"}},
+		{
+			name: "time sheet",
+			model: goapi.ProfileTimeSheet{
+				TimeIn:    goapi.NewTimestamp(now),
+				TimeOut:   &earlier,
+				Total:     goapi.MustParseMoney("-1"),
+				UserEmail: "bad@",
+			},
+			want: []string{"user_profile_id", "time_out", "total", "user_email"},
+		},
+		{
+			name:  "valid reimbursement",
+			model: goapi.ProfileReimbursement{UserProfileID: "p1", Date: goapi.Date{Year: 2024, Month: 1, Day: 2}, Amount: goapi.MustParseMoney("5")},
+		},
+		{
+			name:  "reimbursement",
+			model: goapi.ProfileReimbursement{Amount: goapi.MustParseMoney("-5"), Status: "aproved"},
+			want:  []string{"user_profile_id", "date", "amount", "status"},
+		},
+		{
+			name: "same-day schedule",
+			model: goapi.ProductSchedule{
+				ProductID: "prod_1",
+				BeginDate: goapi.Date{Year: 2024, Month: 5, Day: 1},
+				EndDate:   goapi.Date{Year: 2024, Month: 5, Day: 1},
+			},
+			want: []string{"end_date"},
+		},
+		{
+			name: "schedule with sessions",
+			model: goapi.ProductSchedule{
+				ProductID: "prod_1",
+				BeginDate: goapi.Date{Year: 2024, Month: 5, Day: 1},
+				TimeZone:  "Mars/Base",
+				Sessions: []goapi.ProductScheduleSession{
+					{Day: goapi.WeekdayMonday, BeginTime: nine, DurationMinutes: 60},
+					{Day: "Munday", BeginTime: &goapi.TimeOfDay{Hour: 25}, Staff: []goapi.ProductScheduleSessionUser{{Role: "boss"}}},
+				},
+			},
+			want: []string{
+				"time_zone",
+				"sessions[1].day",
+				"sessions[1].begin_time",
+				"sessions[1].duration_minutes",
+				"sessions[1].staff[0].user_id",
+			},
+		},
+		{
+			name:  "session without begin time",
+			model: goapi.ProductScheduleSession{ProductScheduleID: "sched_1", Day: goapi.WeekdayFriday, DurationMinutes: 30},
+			want:  []string{"begin_time"},
+		},
+		{
+			name:  "resource",
+			model: goapi.ProductScheduleSessionResource{BeginTime: nine},
+			want:  []string{"product_schedule_session_id", "resource_id", "duration_minutes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var verr *goapi.ValidationError
+			if !errors.As(err, &verr) || !errors.Is(err, goapi.ErrValidation) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			var fields []string
+			for _, fe := range verr.FieldErrors {
+				fields = append(fields, fe.Field)
+			}
+			if !slices.Equal(fields, tt.want) {
+				t.Errorf("fields = %q, want %q", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithValidation(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := goapi.New(srv.URL, goapi.WithValidation())
+	if _, err := client.CreateProduct(goapi.Product{}); !errors.Is(err, goapi.ErrValidation) {
+		t.Errorf("CreateProduct: %v, want validation error", err)
+	}
+	if _, err := client.UpdateTimeSheet("ts_1", goapi.ProfileTimeSheet{}); !errors.Is(err, goapi.ErrValidation) {
+		t.Errorf("UpdateTimeSheet: %v, want validation error", err)
+	}
+	if got := hits.Load(); got != 0 {
+		t.Fatalf("invalid models sent %d requests", got)
+	}
+
+	// Clocking in sends a partial time sheet and is not validated
+	if _, err := client.ClockIn("prof_1", goapi.ProfileTimeSheet{}); err != nil {
+		t.Errorf("ClockIn: %v", err)
+	}
+	if _, err := goapi.New(srv.URL).CreateProduct(goapi.Product{}); err != nil {
+		t.Errorf("CreateProduct without validation: %v", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}